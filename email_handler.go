@@ -4,62 +4,320 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
+	"os"
+	"sync"
+	"text/template"
 	"time"
 )
 
-// emailHandler passes minLevel and above slog messages to the smtp client.
+// emailBatchBuffer bounds the number of records queued awaiting a flush;
+// once full, new records are dropped rather than blocking the logger.
+const emailBatchBuffer = 1000
+
+const defaultEmailFlushInterval = 30 * time.Second
+const defaultEmailMaxBatchSize = 50
+
+const defaultEmailSubjectTemplate = `Log Alert ({{.Count}} record{{if ne .Count 1}}s{{end}})`
+
+var defaultEmailBodyTemplate = template.Must(template.New("emailBody").Parse(
+	`{{range .Records}}Level:   {{.Level}}
+Time:    {{.Time}}
+Message: {{.Message}}
+Attrs:   {{range $k, $v := .Attrs}}{{$k}}={{$v}} {{end}}
+
+{{end}}`))
+
+// emailTemplate is satisfied by both *text/template.Template and
+// *html/template.Template, letting EmailOptions.BodyTemplate accept either.
+type emailTemplate interface {
+	Execute(wr io.Writer, data any) error
+}
+
+// EmailOptions configures the batched email handler's flushing, rate
+// limiting, and rendering behavior.
+type EmailOptions struct {
+	// FlushInterval batches records for at most this long before sending a
+	// digest email. Defaults to 30s if zero.
+	FlushInterval time.Duration
+
+	// MaxBatchSize flushes early once this many records have queued up.
+	// Defaults to 50 if zero.
+	MaxBatchSize int
+
+	// MaxEmailsPerHour caps the rate of outgoing digest emails via a
+	// token-bucket limiter. A value of 0 disables the limit.
+	MaxEmailsPerHour int
+
+	// SubjectTemplate is a text/template string executed against an
+	// emailDigest to produce the subject line. Defaults to a generic subject
+	// if empty.
+	SubjectTemplate string
+
+	// BodyTemplate renders the digest body. Accepts a *text/template.Template
+	// or *html/template.Template. Defaults to a plain-text table of level,
+	// time, message, and attrs if nil.
+	BodyTemplate emailTemplate
+
+	// UseTLS dials the SMTP server over implicit TLS.
+	UseTLS bool
+
+	// UseSTARTTLS upgrades a plaintext connection to TLS via STARTTLS.
+	UseSTARTTLS bool
+
+	// CC and BCC are additional recipients on every digest email.
+	CC  []string
+	BCC []string
+}
+
+// emailRecordView is the per-record data made available to email templates.
+type emailRecordView struct {
+	Level   string
+	Time    string
+	Message string
+	Attrs   map[string]string
+}
+
+// emailDigest is the data made available to email templates for a batch.
+type emailDigest struct {
+	Records []emailRecordView
+	Count   int
+}
+
+// emailCore is the background batching/sending state shared by an
+// emailHandler and every handler WithAttrs derives from it.
+type emailCore struct {
+	Level slog.Level
+
+	smtpClient      *smtpClient
+	subjectTemplate *template.Template
+	bodyTemplate    emailTemplate
+	limiter         *rateLimiter
+
+	records chan slog.Record
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// emailHandler batches minLevel and above slog messages into digest emails.
 // It satisfies slog.Handler.
 type emailHandler struct {
-	smtpClient *smtpClient
-	Level      slog.Level
+	core  *emailCore
+	attrs []slog.Attr
 }
 
-// newEmailHandler creates a custom slog.Handler that emits emails.
-func newEmailHandler(sc *smtpClient, level slog.Level) *emailHandler {
-	return &emailHandler{
-		Level:      level,
-		smtpClient: sc,
+// newEmailHandler creates a custom slog.Handler that batches and emails records.
+func newEmailHandler(sc *smtpClient, level slog.Level, opts EmailOptions) *emailHandler {
+	flushInterval := opts.FlushInterval
+	if flushInterval <= 0 {
+		flushInterval = defaultEmailFlushInterval
+	}
+	maxBatch := opts.MaxBatchSize
+	if maxBatch <= 0 {
+		maxBatch = defaultEmailMaxBatchSize
+	}
+
+	subjectSrc := opts.SubjectTemplate
+	if subjectSrc == "" {
+		subjectSrc = defaultEmailSubjectTemplate
+	}
+
+	bodyTmpl := opts.BodyTemplate
+	if bodyTmpl == nil {
+		bodyTmpl = defaultEmailBodyTemplate
 	}
+
+	core := &emailCore{
+		Level:           level,
+		smtpClient:      sc,
+		subjectTemplate: template.Must(template.New("emailSubject").Parse(subjectSrc)),
+		bodyTemplate:    bodyTmpl,
+		limiter:         newRateLimiter(opts.MaxEmailsPerHour),
+		records:         make(chan slog.Record, emailBatchBuffer),
+		done:            make(chan struct{}),
+	}
+
+	core.wg.Add(1)
+	go core.run(flushInterval, maxBatch)
+
+	return &emailHandler{core: core}
 }
 
-// Enabled determines if a slog message will be passed to the smtp client.
+// run coalesces incoming records into batches, flushing on whichever comes
+// first: maxBatch records queued, or flushInterval elapsing.
+func (core *emailCore) run(flushInterval time.Duration, maxBatch int) {
+	defer core.wg.Done()
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	var batch []slog.Record
+	for {
+		select {
+		case r := <-core.records:
+			batch = append(batch, r)
+			if len(batch) >= maxBatch {
+				core.flush(batch)
+				batch = nil
+			}
+
+		case <-ticker.C:
+			if len(batch) > 0 {
+				core.flush(batch)
+				batch = nil
+			}
+
+		case <-core.done:
+			// Drain whatever queued up since the last flush, then send it.
+			for {
+				select {
+				case r := <-core.records:
+					batch = append(batch, r)
+				default:
+					if len(batch) > 0 {
+						core.flush(batch)
+					}
+					return
+				}
+			}
+		}
+	}
+}
+
+// flush renders and sends a single digest email for batch, subject to the
+// rate limiter.
+func (core *emailCore) flush(batch []slog.Record) {
+	if !core.limiter.Allow() {
+		fmt.Fprintf(os.Stderr, "multislog: email rate limit exceeded, dropping %d records\n", len(batch))
+		return
+	}
+
+	digest := emailDigest{Count: len(batch)}
+	for _, r := range batch {
+		view := emailRecordView{
+			Level:   r.Level.String(),
+			Time:    r.Time.Format(time.RFC3339),
+			Message: r.Message,
+			Attrs:   map[string]string{},
+		}
+		r.Attrs(func(a slog.Attr) bool {
+			view.Attrs[a.Key] = a.Value.String()
+			return true
+		})
+		digest.Records = append(digest.Records, view)
+	}
+
+	var subjectBuf, bodyBuf bytes.Buffer
+	if err := core.subjectTemplate.Execute(&subjectBuf, digest); err != nil {
+		fmt.Fprintf(os.Stderr, "multislog: failed to render email subject: %v\n", err)
+		return
+	}
+	if err := core.bodyTemplate.Execute(&bodyBuf, digest); err != nil {
+		fmt.Fprintf(os.Stderr, "multislog: failed to render email body: %v\n", err)
+		return
+	}
+
+	if err := core.smtpClient.Send(subjectBuf.String(), bodyBuf.String()); err != nil {
+		fmt.Fprintf(os.Stderr, "multislog: failed to send digest email: %v\n", err)
+	}
+}
+
+// Enabled determines if a slog message will be queued for email.
 func (eh *emailHandler) Enabled(_ context.Context, level slog.Level) bool {
-	return level >= eh.Level
+	return level >= eh.core.Level
 }
 
-// Handle the emailing of the slog message.
+// Handle queues the slog message, with any attrs bound via WithAttrs merged
+// in, for the next batched digest email.
 func (eh *emailHandler) Handle(_ context.Context, r slog.Record) error {
-	if r.Level < eh.Level {
-		return nil
+	if len(eh.attrs) > 0 {
+		r = r.Clone()
+		r.AddAttrs(eh.attrs...)
 	}
-
-	var buf bytes.Buffer
-	r.Attrs(func(a slog.Attr) bool {
-		fmt.Fprintf(&buf, "%s=%v ", a.Key, a.Value)
-		return true
-	})
-
-	msg := fmt.Sprintf(
-		"Level: %s\nTime: %s\nMessage: %s\nAttributes: %s",
-		r.Level.String(),
-		r.Time.Format(time.RFC3339),
-		r.Message,
-		buf.String(),
-	)
-	err := eh.smtpClient.Send("Log Alert", msg, eh.smtpClient.Recipient)
-	if err != nil {
-		return fmt.Errorf("logger couldn't send emailr: %w", err)
+	select {
+	case eh.core.records <- r:
+		return nil
+	default:
+		return fmt.Errorf("email handler queue full, dropping record")
 	}
-	return nil
 }
 
-// WithAttrs satisfies handler interface.
-func (eh *emailHandler) WithAttrs(_ []slog.Attr) slog.Handler {
-	return eh
+// WithAttrs returns a handler that shares this one's background batcher but
+// binds attrs into every subsequent record, matching the slog.Logger.With
+// contract.
+func (eh *emailHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return eh
+	}
+	merged := make([]slog.Attr, 0, len(eh.attrs)+len(attrs))
+	merged = append(merged, eh.attrs...)
+	merged = append(merged, attrs...)
+	return &emailHandler{core: eh.core, attrs: merged}
 }
 
 // WithGroup satisfies handler interface.
+//
+// Groups aren't supported: the digest template flattens every record's
+// attrs into a single Attrs map, so there's nowhere to nest a group's attrs.
+// Returning eh unchanged means grouped attrs are currently rendered
+// ungrouped rather than dropped.
 func (eh *emailHandler) WithGroup(_ string) slog.Handler {
 	return eh
 }
+
+// Close drains any queued records, flushes a final digest if needed, and
+// stops the background batching goroutine.
+func (eh *emailHandler) Close() error {
+	close(eh.core.done)
+	eh.core.wg.Wait()
+	return nil
+}
+
+// rateLimiter is a simple token-bucket limiter used to cap outgoing email volume.
+type rateLimiter struct {
+	mu       sync.Mutex
+	tokens   float64
+	max      float64
+	refill   float64 // tokens added per second
+	last     time.Time
+	disabled bool
+}
+
+// newRateLimiter creates a token bucket capped at maxPerHour tokens per hour.
+// A maxPerHour of 0 or less disables limiting entirely.
+func newRateLimiter(maxPerHour int) *rateLimiter {
+	if maxPerHour <= 0 {
+		return &rateLimiter{disabled: true}
+	}
+	max := float64(maxPerHour)
+	return &rateLimiter{
+		tokens: max,
+		max:    max,
+		refill: max / 3600,
+		last:   time.Now(),
+	}
+}
+
+// Allow reports whether a token is available and, if so, consumes it.
+func (rl *rateLimiter) Allow() bool {
+	if rl.disabled {
+		return true
+	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	rl.tokens += now.Sub(rl.last).Seconds() * rl.refill
+	if rl.tokens > rl.max {
+		rl.tokens = rl.max
+	}
+	rl.last = now
+
+	if rl.tokens < 1 {
+		return false
+	}
+	rl.tokens--
+	return true
+}
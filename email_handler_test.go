@@ -1,10 +1,16 @@
 package multislog
 
 import (
+	"bufio"
 	"context"
 	"errors"
 	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 var errSMTPFailure = errors.New("smtp failure")
@@ -43,7 +49,7 @@ func TestClose_EmailHandler_CloseCalled(t *testing.T) {
 
 	ms := &Multislog{
 		Logger:   slog.New(h),
-		handlers: []slog.Handler{h},
+		handlers: newHandlerSet([]slog.Handler{h}),
 	}
 
 	ms.Close()
@@ -59,7 +65,7 @@ func TestClose_EmailHandler_CloseErrorIgnored(t *testing.T) {
 
 	ms := &Multislog{
 		Logger:   slog.New(h1),
-		handlers: []slog.Handler{h1, h2},
+		handlers: newHandlerSet([]slog.Handler{h1, h2}),
 	}
 
 	// Must not panic
@@ -69,3 +75,300 @@ func TestClose_EmailHandler_CloseErrorIgnored(t *testing.T) {
 		t.Fatal("expected all handlers to be closed even after error")
 	}
 }
+
+// fakeSMTPServer speaks just enough SMTP to satisfy net/smtp.SendMail
+// (it advertises only AUTH PLAIN, which net/smtp allows without TLS against
+// 127.0.0.1, and no STARTTLS, so the client skips the upgrade) and records
+// each message's subject line, recipient count, header block and body.
+type fakeSMTPServer struct {
+	listener net.Listener
+
+	mu        sync.Mutex
+	subjects  []string
+	toCounts  []int
+	rawHeader []string
+	bodies    []string
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &fakeSMTPServer{listener: l}
+	go s.acceptLoop()
+	t.Cleanup(func() { _ = l.Close() })
+	return s
+}
+
+func (s *fakeSMTPServer) hostPort() (string, string) {
+	addr := s.listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), strconv.Itoa(addr.Port)
+}
+
+func (s *fakeSMTPServer) acceptLoop() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.serve(conn)
+	}
+}
+
+func (s *fakeSMTPServer) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+
+	reply := func(line string) {
+		_, _ = w.WriteString(line + "\r\n")
+		_ = w.Flush()
+	}
+
+	reply("220 fake smtp ready")
+	toCount := 0
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		switch {
+		case strings.HasPrefix(line, "EHLO"), strings.HasPrefix(line, "HELO"):
+			_, _ = w.WriteString("250-fake smtp\r\n250 AUTH PLAIN\r\n")
+			_ = w.Flush()
+		case strings.HasPrefix(line, "AUTH PLAIN"):
+			reply("235 authentication successful")
+		case strings.HasPrefix(line, "MAIL FROM"):
+			reply("250 OK")
+		case strings.HasPrefix(line, "RCPT TO"):
+			toCount++
+			reply("250 OK")
+		case strings.HasPrefix(line, "DATA"):
+			reply("354 go ahead")
+			subject, header, body := s.readData(r)
+			s.mu.Lock()
+			s.subjects = append(s.subjects, subject)
+			s.toCounts = append(s.toCounts, toCount)
+			s.rawHeader = append(s.rawHeader, header)
+			s.bodies = append(s.bodies, body)
+			s.mu.Unlock()
+			toCount = 0
+			reply("250 OK: queued")
+		case strings.HasPrefix(line, "QUIT"):
+			reply("221 bye")
+			return
+		default:
+			reply("500 unrecognized command")
+		}
+	}
+}
+
+// readData reads DATA-mode lines until the "." terminator, returning the
+// message's Subject header value, the raw header block (every line up to the
+// first blank line, so tests can check for injected header lines), and the
+// body.
+func (s *fakeSMTPServer) readData(r *bufio.Reader) (subject, header, body string) {
+	inHeader := true
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return subject, header, body
+		}
+		trimmed := strings.TrimRight(line, "\r\n")
+		if trimmed == "." {
+			return subject, header, body
+		}
+		if inHeader {
+			if trimmed == "" {
+				inHeader = false
+				continue
+			}
+			header += trimmed + "\n"
+			if v, ok := strings.CutPrefix(trimmed, "Subject: "); ok {
+				subject = v
+			}
+			continue
+		}
+		body += trimmed + "\n"
+	}
+}
+
+func (s *fakeSMTPServer) messageCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.subjects)
+}
+
+func (s *fakeSMTPServer) lastSubject() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.subjects) == 0 {
+		return ""
+	}
+	return s.subjects[len(s.subjects)-1]
+}
+
+func (s *fakeSMTPServer) lastHeader() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.rawHeader) == 0 {
+		return ""
+	}
+	return s.rawHeader[len(s.rawHeader)-1]
+}
+
+func (s *fakeSMTPServer) lastBody() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.bodies) == 0 {
+		return ""
+	}
+	return s.bodies[len(s.bodies)-1]
+}
+
+func waitForCondition(t *testing.T, timeout time.Duration, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if !cond() {
+		t.Fatal("condition not met before timeout")
+	}
+}
+
+func TestEmailHandler_FlushesOnMaxBatchSize(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	host, port := server.hostPort()
+	sc := newSMTPClient(host, port, "", "", "from@example.com", "to@example.com")
+
+	eh := newEmailHandler(sc, slog.LevelInfo, EmailOptions{
+		FlushInterval: time.Hour, // long enough that only MaxBatchSize can trigger the flush
+		MaxBatchSize:  2,
+	})
+	defer eh.Close()
+
+	for i := 0; i < 2; i++ {
+		r := slog.NewRecord(time.Now(), slog.LevelError, "boom", 0)
+		if err := eh.Handle(context.Background(), r); err != nil {
+			t.Fatalf("Handle: %v", err)
+		}
+	}
+
+	waitForCondition(t, 2*time.Second, func() bool { return server.messageCount() == 1 })
+	if !strings.Contains(server.lastSubject(), "2 records") {
+		t.Fatalf("expected subject to report 2 records, got %q", server.lastSubject())
+	}
+}
+
+func TestEmailHandler_FlushesOnInterval(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	host, port := server.hostPort()
+	sc := newSMTPClient(host, port, "", "", "from@example.com", "to@example.com")
+
+	eh := newEmailHandler(sc, slog.LevelInfo, EmailOptions{
+		FlushInterval: 30 * time.Millisecond,
+		MaxBatchSize:  1000, // high enough that only the interval can trigger the flush
+	})
+	defer eh.Close()
+
+	r := slog.NewRecord(time.Now(), slog.LevelWarn, "slow disk", 0)
+	if err := eh.Handle(context.Background(), r); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+
+	waitForCondition(t, 2*time.Second, func() bool { return server.messageCount() == 1 })
+	if !strings.Contains(server.lastSubject(), "1 record)") {
+		t.Fatalf("expected subject to report 1 record, got %q", server.lastSubject())
+	}
+}
+
+func TestEmailHandler_CloseFlushesPendingBatch(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	host, port := server.hostPort()
+	sc := newSMTPClient(host, port, "", "", "from@example.com", "to@example.com")
+
+	eh := newEmailHandler(sc, slog.LevelInfo, EmailOptions{
+		FlushInterval: time.Hour,
+		MaxBatchSize:  1000,
+	})
+
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "shutting down", 0)
+	if err := eh.Handle(context.Background(), r); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+
+	if err := eh.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if got := server.messageCount(); got != 1 {
+		t.Fatalf("expected Close to flush the pending record, got %d messages", got)
+	}
+}
+
+func TestEmailHandler_WithAttrsBindsAttrsIntoDigest(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	host, port := server.hostPort()
+	sc := newSMTPClient(host, port, "", "", "from@example.com", "to@example.com")
+
+	eh := newEmailHandler(sc, slog.LevelInfo, EmailOptions{
+		FlushInterval: time.Hour,
+		MaxBatchSize:  1,
+	})
+	defer eh.Close()
+
+	bound := eh.WithAttrs([]slog.Attr{slog.String("request_id", "abc123")})
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "hello", 0)
+	if err := bound.Handle(context.Background(), r); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+
+	waitForCondition(t, 2*time.Second, func() bool { return server.messageCount() == 1 })
+	if !strings.Contains(server.lastBody(), "request_id=abc123") {
+		t.Fatalf("expected bound attrs in digest body, got %q", server.lastBody())
+	}
+}
+
+func TestRateLimiter_AllowsUpToMaxThenBlocks(t *testing.T) {
+	rl := newRateLimiter(1)
+
+	if !rl.Allow() {
+		t.Fatal("expected the first email within the hourly cap to be allowed")
+	}
+	if rl.Allow() {
+		t.Fatal("expected a second email within the same window to be blocked")
+	}
+}
+
+func TestRateLimiter_RefillsOverTime(t *testing.T) {
+	rl := newRateLimiter(3600) // one token per second, so refill is easy to observe
+
+	if !rl.Allow() {
+		t.Fatal("expected initial token to be available")
+	}
+	// Empty the bucket so only a refill can supply the next token, then
+	// simulate one second (one token's worth) elapsing without sleeping.
+	rl.tokens = 0
+	rl.last = rl.last.Add(-time.Second)
+
+	if !rl.Allow() {
+		t.Fatal("expected a token to have refilled after the simulated elapsed time")
+	}
+}
+
+func TestRateLimiter_Disabled(t *testing.T) {
+	rl := newRateLimiter(0)
+	for i := 0; i < 10; i++ {
+		if !rl.Allow() {
+			t.Fatal("expected a disabled rate limiter (maxPerHour=0) to always allow")
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package multislog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+var errHandlerNotFound = errors.New("handler not registered")
+
+// HandlerFunc adapts a plain function to slog.Handler, for callers who want
+// to attach simple record-processing logic without writing a full
+// slog.Handler implementation.
+type HandlerFunc func(context.Context, slog.Record) error
+
+// handlerFuncAdapter wraps a HandlerFunc to satisfy slog.Handler.
+type handlerFuncAdapter struct {
+	Level slog.Level
+	fn    HandlerFunc
+	attrs []slog.Attr
+}
+
+// Enabled determines if a slog message will be passed to fn.
+func (a *handlerFuncAdapter) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= a.Level
+}
+
+// Handle passes the slog message to fn, with any attrs bound via WithAttrs
+// merged in.
+func (a *handlerFuncAdapter) Handle(ctx context.Context, r slog.Record) error {
+	if len(a.attrs) > 0 {
+		r = r.Clone()
+		r.AddAttrs(a.attrs...)
+	}
+	return a.fn(ctx, r)
+}
+
+// WithAttrs returns a handler that shares fn but binds attrs into every
+// subsequent record, matching the slog.Logger.With contract.
+func (a *handlerFuncAdapter) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return a
+	}
+	merged := make([]slog.Attr, 0, len(a.attrs)+len(attrs))
+	merged = append(merged, a.attrs...)
+	merged = append(merged, attrs...)
+	return &handlerFuncAdapter{Level: a.Level, fn: a.fn, attrs: merged}
+}
+
+// WithGroup satisfies handler interface.
+func (a *handlerFuncAdapter) WithGroup(_ string) slog.Handler {
+	return a
+}
+
+// EnableHandler attaches an arbitrary third-party slog.Handler (Loki, Sentry,
+// Datadog, a zap bridge, a Slack webhook, etc.) to the multihandler fan-out,
+// without waiting for first-class support in this module.
+//
+// If h also implements `interface{ Close() error }`, Multislog.Close() closes
+// it along with the built-in handlers.
+func EnableHandler(h slog.Handler) Option {
+	return func(ms *Multislog) error {
+		ms.handlers.append(h)
+		return nil
+	}
+}
+
+// EnableHandlerFunc attaches fn as a slog.Handler processing all logs above
+// "level". It's a convenience over EnableHandler for callers who only need a
+// Handle function and not a full slog.Handler implementation.
+func EnableHandlerFunc(level slog.Level, fn HandlerFunc) Option {
+	return EnableHandler(&handlerFuncAdapter{Level: level, fn: fn})
+}
+
+// RemoveHandler detaches h from the multihandler fan-out. It returns an error
+// if h was never registered (or has already been removed).
+//
+// If h also implements `interface{ Close() error }`, it is closed on removal
+// so any background goroutines, tickers, or connections it owns (e.g. the
+// email or syslog handlers) aren't leaked. A Close failure is printed to
+// stderr rather than returned, since h has already been detached either way.
+//
+// It is safe to call concurrently with logging and with ReplaceHandler.
+func (ms *Multislog) RemoveHandler(h slog.Handler) error {
+	if !ms.handlers.remove(h) {
+		return errHandlerNotFound
+	}
+	closeHandler(h)
+	return nil
+}
+
+// ReplaceHandler swaps oldHandler for newHandler in the multihandler
+// fan-out, e.g. to rotate an email destination without restarting the
+// process. It returns an error if oldHandler was never registered.
+//
+// oldHandler is closed on replacement the same way RemoveHandler closes a
+// removed handler.
+//
+// It is safe to call concurrently with logging and with RemoveHandler.
+func (ms *Multislog) ReplaceHandler(oldHandler, newHandler slog.Handler) error {
+	if !ms.handlers.replace(oldHandler, newHandler) {
+		return errHandlerNotFound
+	}
+	closeHandler(oldHandler)
+	return nil
+}
+
+// closeHandler closes h if it implements `interface{ Close() error }`,
+// printing any failure to stderr rather than propagating it: by the time
+// this runs, h has already been detached from the fan-out either way.
+func closeHandler(h slog.Handler) {
+	c, ok := h.(interface{ Close() error })
+	if !ok {
+		return
+	}
+	if err := c.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "multislog: failed to close detached handler: %v\n", err)
+	}
+}
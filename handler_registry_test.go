@@ -0,0 +1,98 @@
+package multislog
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+)
+
+func TestEnableHandlerFunc_ReceivesRecords(t *testing.T) {
+	var got slog.Record
+	ms := New(EnableHandlerFunc(slog.LevelInfo, func(_ context.Context, r slog.Record) error {
+		got = r
+		return nil
+	}))
+	defer ms.Close()
+
+	ms.Logger.Info("hello handler func")
+	if got.Message != "hello handler func" {
+		t.Fatalf("expected record to reach handler func, got %q", got.Message)
+	}
+}
+
+func TestHandlerFuncAdapter_WithAttrsBindsAttrsIntoRecord(t *testing.T) {
+	var got slog.Record
+	ms := New(EnableHandlerFunc(slog.LevelInfo, func(_ context.Context, r slog.Record) error {
+		got = r
+		return nil
+	}))
+	defer ms.Close()
+
+	ms.Logger.With("request_id", "abc123").Info("hello")
+
+	var attrs []string
+	got.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a.String())
+		return true
+	})
+	if len(attrs) != 1 || attrs[0] != "request_id=abc123" {
+		t.Fatalf("expected bound attrs to reach the handler func, got %v", attrs)
+	}
+}
+
+func TestRemoveHandler(t *testing.T) {
+	h := &closingHandler{}
+	ms := New(EnableHandler(h))
+	defer ms.Close()
+
+	if err := ms.RemoveHandler(h); err != nil {
+		t.Fatalf("RemoveHandler failed: %v", err)
+	}
+	if len(ms.handlers.snapshot()) != 0 {
+		t.Fatalf("expected handler to be removed")
+	}
+	if !h.closed {
+		t.Fatal("expected RemoveHandler to close the detached handler")
+	}
+}
+
+func TestRemoveHandler_NotRegistered(t *testing.T) {
+	ms := New()
+	defer ms.Close()
+
+	if err := ms.RemoveHandler(&closingHandler{}); err == nil {
+		t.Fatal("expected error removing an unregistered handler")
+	}
+}
+
+func TestReplaceHandler(t *testing.T) {
+	oldHandler := &closingHandler{}
+	newHandler := &closingHandler{}
+	ms := New(EnableHandler(oldHandler))
+	defer ms.Close()
+
+	if err := ms.ReplaceHandler(oldHandler, newHandler); err != nil {
+		t.Fatalf("ReplaceHandler failed: %v", err)
+	}
+
+	handlers := ms.handlers.snapshot()
+	if len(handlers) != 1 || handlers[0] != slog.Handler(newHandler) {
+		t.Fatalf("expected newHandler to replace oldHandler, got %+v", handlers)
+	}
+	if !oldHandler.closed {
+		t.Fatal("expected ReplaceHandler to close the detached oldHandler")
+	}
+	if newHandler.closed {
+		t.Fatal("expected the replacing newHandler to remain open")
+	}
+}
+
+func TestRemoveHandler_CloseFailureIsNonFatal(t *testing.T) {
+	h := &closingHandler{failClose: true}
+	ms := New(EnableHandler(h))
+	defer ms.Close()
+
+	if err := ms.RemoveHandler(h); err != nil {
+		t.Fatalf("RemoveHandler should succeed even if the handler's Close fails: %v", err)
+	}
+}
@@ -0,0 +1,256 @@
+package multislog
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// journaldSocketPath is a var rather than a const so tests can point it at a
+// temporary socket instead of the real journald.
+var journaldSocketPath = "/run/systemd/journal/socket"
+
+const journaldQueueBuffer = 1000
+
+// journaldCore is the background writer state shared by a journaldHandler
+// and every handler WithAttrs derives from it.
+type journaldCore struct {
+	Level slog.Level
+
+	datagrams chan []byte
+	done      chan struct{}
+	wg        sync.WaitGroup
+
+	// conn and backoff are only touched by the background run() goroutine.
+	conn    net.Conn
+	backoff time.Duration
+}
+
+// journaldHandler forwards minLevel and above slog messages to systemd-journald
+// over its native datagram protocol. It satisfies slog.Handler.
+//
+// Like syslogHandler, datagrams are queued and written by a single background
+// goroutine that reconnects with exponential backoff, so a journald restart
+// (a normal systemd-unit-upgrade event) doesn't permanently break forwarding
+// or block the caller's Handle().
+type journaldHandler struct {
+	core  *journaldCore
+	attrs []slog.Attr
+}
+
+// newJournaldHandler creates a custom slog.Handler that forwards records to journald.
+func newJournaldHandler(level slog.Level) (*journaldHandler, error) {
+	core := &journaldCore{
+		Level:     level,
+		datagrams: make(chan []byte, journaldQueueBuffer),
+		done:      make(chan struct{}),
+	}
+	if err := core.connect(); err != nil {
+		return nil, fmt.Errorf("dial journald socket: %w", err)
+	}
+
+	core.wg.Add(1)
+	go core.run()
+
+	return &journaldHandler{core: core}, nil
+}
+
+// run owns core.conn and core.backoff exclusively, writing queued datagrams
+// and reconnecting (with backoff) off the caller's goroutine.
+func (core *journaldCore) run() {
+	defer core.wg.Done()
+
+	for {
+		select {
+		case datagram := <-core.datagrams:
+			core.writeWithReconnect(datagram)
+
+		case <-core.done:
+			// Drain whatever queued up since the last write, then exit.
+			for {
+				select {
+				case datagram := <-core.datagrams:
+					core.writeWithReconnect(datagram)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// writeWithReconnect writes datagram to journald, reconnecting (and sleeping
+// through backoff) on this goroutine only if necessary.
+func (core *journaldCore) writeWithReconnect(datagram []byte) {
+	if core.conn == nil {
+		if err := core.connectWithBackoff(); err != nil {
+			fmt.Fprintf(os.Stderr, "multislog: journald unreachable, dropping record: %v\n", err)
+			return
+		}
+	}
+
+	if _, err := core.conn.Write(datagram); err != nil {
+		_ = core.conn.Close()
+		core.conn = nil
+
+		if err := core.connectWithBackoff(); err != nil {
+			fmt.Fprintf(os.Stderr, "multislog: journald reconnect failed, dropping record: %v\n", err)
+			return
+		}
+		if _, err := core.conn.Write(datagram); err != nil {
+			fmt.Fprintf(os.Stderr, "multislog: journald write failed after reconnect, dropping record: %v\n", err)
+		}
+	}
+}
+
+// connect dials the journald socket, bounding the attempt with
+// syslogDialTimeout so a stuck socket never hangs the background goroutine
+// indefinitely.
+func (core *journaldCore) connect() error {
+	conn, err := net.DialTimeout("unixgram", journaldSocketPath, syslogDialTimeout)
+	if err != nil {
+		return err
+	}
+	core.conn = conn
+	return nil
+}
+
+// connectWithBackoff retries connect(), sleeping through an exponential
+// backoff on failure. It only ever runs on the background run() goroutine,
+// so the sleep never blocks a caller logging through Handle().
+func (core *journaldCore) connectWithBackoff() error {
+	err := core.connect()
+	if err == nil {
+		core.backoff = 0
+		return nil
+	}
+
+	if core.backoff == 0 {
+		core.backoff = syslogMinBackoff
+	}
+	time.Sleep(core.backoff)
+	core.backoff *= 2
+	if core.backoff > syslogMaxBackoff {
+		core.backoff = syslogMaxBackoff
+	}
+	return err
+}
+
+// Enabled determines if a slog message will be forwarded to journald.
+func (jh *journaldHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= jh.core.Level
+}
+
+// journaldPriority maps a slog level onto journald's syslog-style PRIORITY field.
+func journaldPriority(level slog.Level) int {
+	switch {
+	case level >= slog.LevelError:
+		return 3 // ERR
+	case level >= slog.LevelWarn:
+		return 4 // WARNING
+	case level >= slog.LevelInfo:
+		return 6 // INFO
+	default:
+		return 7 // DEBUG
+	}
+}
+
+// Handle renders the slog message, including any attrs bound via WithAttrs,
+// as journald fields and queues it for the background writer. If the queue
+// is full (journald can't keep up), the record is dropped rather than
+// blocking the caller.
+func (jh *journaldHandler) Handle(_ context.Context, r slog.Record) error {
+	var buf bytes.Buffer
+	writeJournaldField(&buf, "MESSAGE", r.Message)
+	writeJournaldField(&buf, "PRIORITY", strconv.Itoa(journaldPriority(r.Level)))
+	writeJournaldField(&buf, "SYSLOG_IDENTIFIER", "multislog")
+
+	for _, a := range jh.attrs {
+		writeJournaldField(&buf, journaldFieldName(a.Key), a.Value.String())
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		writeJournaldField(&buf, journaldFieldName(a.Key), a.Value.String())
+		return true
+	})
+
+	select {
+	case jh.core.datagrams <- buf.Bytes():
+		return nil
+	default:
+		return fmt.Errorf("journald handler queue full, dropping record")
+	}
+}
+
+// writeJournaldField appends a single field in journald's native KEY=VALUE\n
+// protocol, switching to the length-prefixed binary form for values
+// containing a newline, as the protocol requires.
+func writeJournaldField(buf *bytes.Buffer, key, value string) {
+	if !strings.Contains(value, "\n") {
+		fmt.Fprintf(buf, "%s=%s\n", key, value)
+		return
+	}
+	fmt.Fprintf(buf, "%s\n", key)
+	var size [8]byte
+	binary.LittleEndian.PutUint64(size[:], uint64(len(value)))
+	buf.Write(size[:])
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
+
+// journaldFieldName upper-cases an attr key and replaces any character
+// journald field names don't allow, since slog attr keys aren't constrained
+// the same way.
+func journaldFieldName(key string) string {
+	key = strings.ToUpper(key)
+	return strings.Map(func(r rune) rune {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			return r
+		}
+		return '_'
+	}, key)
+}
+
+// WithAttrs returns a handler that shares this one's background writer but
+// renders attrs on every subsequent record, matching the slog.Logger.With
+// contract.
+func (jh *journaldHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return jh
+	}
+	merged := make([]slog.Attr, 0, len(jh.attrs)+len(attrs))
+	merged = append(merged, jh.attrs...)
+	merged = append(merged, attrs...)
+	return &journaldHandler{core: jh.core, attrs: merged}
+}
+
+// WithGroup satisfies handler interface.
+//
+// Groups aren't supported: every journal field is already flat and
+// top-level, so there's nowhere to nest a group's attrs. Returning jh
+// unchanged means grouped attrs are currently rendered ungrouped rather than
+// dropped.
+func (jh *journaldHandler) WithGroup(_ string) slog.Handler {
+	return jh
+}
+
+// Close stops the background writer (after draining queued datagrams) and
+// closes the underlying journald socket connection.
+func (jh *journaldHandler) Close() error {
+	close(jh.core.done)
+	jh.core.wg.Wait()
+
+	if jh.core.conn == nil {
+		return nil
+	}
+	err := jh.core.conn.Close()
+	jh.core.conn = nil
+	return err
+}
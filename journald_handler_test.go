@@ -0,0 +1,188 @@
+package multislog
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJournaldHandler_WritesNativeProtocol(t *testing.T) {
+	sockPath := filepath.Join(t.TempDir(), "journal.sock")
+
+	listener, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: sockPath, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer listener.Close()
+
+	orig := journaldSocketPath
+	journaldSocketPath = sockPath
+	defer func() { journaldSocketPath = orig }()
+
+	jh, err := newJournaldHandler(slog.LevelInfo)
+	if err != nil {
+		t.Fatalf("newJournaldHandler failed: %v", err)
+	}
+	defer jh.Close()
+
+	r := slog.NewRecord(time.Now(), slog.LevelError, "disk full", 0)
+	r.AddAttrs(slog.String("path", "/var"))
+	if err := jh.Handle(context.Background(), r); err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	_ = listener.SetReadDeadline(time.Now().Add(2 * time.Second))
+	buf := make([]byte, 4096)
+	n, err := listener.Read(buf)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+
+	got := string(buf[:n])
+	for _, want := range []string{"MESSAGE=disk full", "PRIORITY=3", "PATH=/var", "SYSLOG_IDENTIFIER=multislog"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected journald datagram to contain %q, got %q", want, got)
+		}
+	}
+}
+
+func TestJournaldHandler_WithAttrsBindsAttrsIntoFields(t *testing.T) {
+	sockPath := filepath.Join(t.TempDir(), "journal.sock")
+
+	listener, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: sockPath, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer listener.Close()
+
+	orig := journaldSocketPath
+	journaldSocketPath = sockPath
+	defer func() { journaldSocketPath = orig }()
+
+	jh, err := newJournaldHandler(slog.LevelInfo)
+	if err != nil {
+		t.Fatalf("newJournaldHandler failed: %v", err)
+	}
+	defer jh.Close()
+
+	bound := jh.WithAttrs([]slog.Attr{slog.String("request_id", "abc123")})
+
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "hello", 0)
+	if err := bound.Handle(context.Background(), r); err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	_ = listener.SetReadDeadline(time.Now().Add(2 * time.Second))
+	buf := make([]byte, 4096)
+	n, err := listener.Read(buf)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+
+	if got := string(buf[:n]); !strings.Contains(got, "REQUEST_ID=abc123") {
+		t.Fatalf("expected bound attrs in journald datagram, got %q", got)
+	}
+}
+
+func TestJournaldHandler_ReconnectsAfterSocketRestart(t *testing.T) {
+	sockPath := filepath.Join(t.TempDir(), "journal.sock")
+
+	listener1, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: sockPath, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	orig := journaldSocketPath
+	journaldSocketPath = sockPath
+	defer func() { journaldSocketPath = orig }()
+
+	jh, err := newJournaldHandler(slog.LevelInfo)
+	if err != nil {
+		t.Fatalf("newJournaldHandler failed: %v", err)
+	}
+	defer jh.Close()
+
+	if err := listener1.Close(); err != nil {
+		t.Fatalf("close listener1: %v", err)
+	}
+	// net.UnixConn.Close doesn't unlink its socket file (only
+	// net.UnixListener does), so remove it ourselves to free the path for
+	// listener2, simulating journald's own restart cleanup.
+	if err := os.Remove(sockPath); err != nil {
+		t.Fatalf("remove socket file: %v", err)
+	}
+
+	r1 := slog.NewRecord(time.Now(), slog.LevelInfo, "before restart", 0)
+	if err := jh.Handle(context.Background(), r1); err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+	// Give the background writer a moment to notice the write failure and
+	// attempt (and fail) its first reconnect before journald comes back.
+	time.Sleep(50 * time.Millisecond)
+
+	listener2, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: sockPath, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("listen again: %v", err)
+	}
+	defer listener2.Close()
+
+	r2 := slog.NewRecord(time.Now(), slog.LevelInfo, "after restart", 0)
+	if err := jh.Handle(context.Background(), r2); err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	_ = listener2.SetReadDeadline(time.Now().Add(2 * time.Second))
+	buf := make([]byte, 4096)
+	n, err := listener2.Read(buf)
+	if err != nil {
+		t.Fatalf("expected handler to reconnect and deliver after journald restarted: %v", err)
+	}
+	if got := string(buf[:n]); !strings.Contains(got, "MESSAGE=after restart") {
+		t.Fatalf("unexpected journald datagram after reconnect: %q", got)
+	}
+}
+
+func TestWriteJournaldField_SingleLine(t *testing.T) {
+	var buf bytes.Buffer
+	writeJournaldField(&buf, "MESSAGE", "hello")
+	if buf.String() != "MESSAGE=hello\n" {
+		t.Fatalf("unexpected field encoding: %q", buf.String())
+	}
+}
+
+func TestWriteJournaldField_MultilineUsesBinaryForm(t *testing.T) {
+	var buf bytes.Buffer
+	value := "line1\nline2"
+	writeJournaldField(&buf, "MESSAGE", value)
+
+	s := buf.Bytes()
+	header := []byte("MESSAGE\n")
+	if !bytes.HasPrefix(s, header) {
+		t.Fatalf("expected binary framing header, got %q", s)
+	}
+
+	rest := s[len(header):]
+	size := binary.LittleEndian.Uint64(rest[:8])
+	if size != uint64(len(value)) {
+		t.Fatalf("length prefix = %d, want %d", size, len(value))
+	}
+	if string(rest[8:8+size]) != value {
+		t.Fatalf("framed value = %q, want %q", rest[8:8+size], value)
+	}
+	if rest[8+size] != '\n' {
+		t.Fatal("expected trailing newline after framed value")
+	}
+}
+
+func TestJournaldFieldName(t *testing.T) {
+	if got := journaldFieldName("request.id"); got != "REQUEST_ID" {
+		t.Fatalf("journaldFieldName(%q) = %q", "request.id", got)
+	}
+}
@@ -0,0 +1,74 @@
+package multislog
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// Run blocks until ctx is canceled or the process receives SIGINT or
+// SIGTERM, then closes ms via Close() so callers don't have to rely solely
+// on a deferred Close() at main() (which, per Close's documentation, never
+// runs on SIGKILL, power loss, kernel panic, or os.Exit).
+//
+// If $NOTIFY_SOCKET is set, Run notifies systemd with READY=1 before
+// blocking and STOPPING=1 once shutdown begins, so multislog cooperates with
+// Type=notify services.
+//
+// shutdownTimeout bounds how long Run waits for Close() to finish; once it
+// elapses, Run returns a timeout error without waiting further. A
+// shutdownTimeout of 0 waits indefinitely.
+func (ms *Multislog) Run(ctx context.Context, shutdownTimeout time.Duration) error {
+	notifySystemd("READY=1")
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	select {
+	case <-ctx.Done():
+	case <-sigCh:
+	}
+
+	notifySystemd("STOPPING=1")
+
+	closed := make(chan error, 1)
+	go func() { closed <- ms.Close() }()
+
+	if shutdownTimeout <= 0 {
+		return <-closed
+	}
+
+	select {
+	case err := <-closed:
+		return err
+	case <-time.After(shutdownTimeout):
+		return fmt.Errorf("multislog: shutdown timed out after %s", shutdownTimeout)
+	}
+}
+
+// notifySystemd writes state (e.g. "READY=1", "STOPPING=1") to
+// $NOTIFY_SOCKET, the protocol systemd Type=notify services use for
+// lifecycle signaling. It is a no-op if $NOTIFY_SOCKET is unset.
+func notifySystemd(state string) {
+	socketPath := os.Getenv("NOTIFY_SOCKET")
+	if socketPath == "" {
+		return
+	}
+
+	addr := &net.UnixAddr{Name: socketPath, Net: "unixgram"}
+	conn, err := net.DialUnix("unixgram", nil, addr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "multislog: failed to notify systemd: %v\n", err)
+		return
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte(state)); err != nil {
+		fmt.Fprintf(os.Stderr, "multislog: failed to notify systemd: %v\n", err)
+	}
+}
@@ -0,0 +1,54 @@
+package multislog
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestRun_ClosesOnContextCancel(t *testing.T) {
+	ms := New(EnableConsole(slog.LevelDebug))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := ms.Run(ctx, time.Second); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+}
+
+func TestRun_ReturnsHandlerCloseErrors(t *testing.T) {
+	ms := &Multislog{handlers: newHandlerSet([]slog.Handler{&mockEmailHandler{fail: true}})}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := ms.Run(ctx, time.Second); err == nil {
+		t.Fatal("expected Run to surface the handler's Close() error")
+	}
+}
+
+func TestRun_ShutdownTimeout(t *testing.T) {
+	slowClose := make(chan struct{})
+	defer close(slowClose)
+
+	ms := &Multislog{handlers: newHandlerSet([]slog.Handler{&slowClosingHandler{unblock: slowClose}})}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := ms.Run(ctx, 10*time.Millisecond); err == nil {
+		t.Fatal("expected Run to time out waiting for a slow Close()")
+	}
+}
+
+type slowClosingHandler struct {
+	closingHandler
+	unblock <-chan struct{}
+}
+
+func (h *slowClosingHandler) Close() error {
+	<-h.unblock
+	return nil
+}
@@ -0,0 +1,241 @@
+package multislog
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// RotationPolicy configures how EnableRotatingLogFile rotates and retains log
+// files so a long-running process doesn't grow its log file unbounded.
+type RotationPolicy struct {
+	// MaxSizeBytes rotates the log file once it would grow beyond this size.
+	// A value of 0 disables size-based rotation.
+	MaxSizeBytes int64
+
+	// MaxAgeDays deletes rotated backups older than this many days.
+	// A value of 0 disables age-based retention.
+	MaxAgeDays int
+
+	// MaxBackups caps the number of rotated backups kept on disk.
+	// A value of 0 keeps every backup.
+	MaxBackups int
+
+	// Compress gzips rotated backups.
+	Compress bool
+}
+
+// rotatingFileWriter wraps an *os.File, rotating it to a timestamped backup
+// once it exceeds policy.MaxSizeBytes and pruning old backups. It also
+// rotates on SIGHUP so external logrotate tools can cooperate. It satisfies
+// io.Writer and io.Closer.
+type rotatingFileWriter struct {
+	mu        sync.Mutex
+	file      *os.File
+	size      int64
+	filename  string
+	baseDir   string
+	allowRead bool
+	policy    RotationPolicy
+
+	sighup chan os.Signal
+	done   chan struct{}
+}
+
+// newRotatingFileWriter opens filename (sandboxed to the executable's
+// directory, same as openLogFile) and installs the SIGHUP rotation trigger.
+func newRotatingFileWriter(filename string, allowRead, clearOnRestart bool, policy RotationPolicy) (*rotatingFileWriter, error) {
+	file, err := openLogFile(filename, allowRead, clearOnRestart)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("stat log file: %w", err)
+	}
+
+	rw := &rotatingFileWriter{
+		file:      file,
+		size:      info.Size(),
+		filename:  filename,
+		baseDir:   filepath.Dir(file.Name()),
+		allowRead: allowRead,
+		policy:    policy,
+		sighup:    make(chan os.Signal, 1),
+		done:      make(chan struct{}),
+	}
+
+	signal.Notify(rw.sighup, syscall.SIGHUP)
+	go rw.watchSIGHUP()
+
+	return rw, nil
+}
+
+func (rw *rotatingFileWriter) watchSIGHUP() {
+	for {
+		select {
+		case <-rw.sighup:
+			rw.mu.Lock()
+			if err := rw.rotateLocked(); err != nil {
+				fmt.Fprintf(os.Stderr, "multislog: SIGHUP rotation failed: %v\n", err)
+			}
+			rw.mu.Unlock()
+		case <-rw.done:
+			return
+		}
+	}
+}
+
+// Write satisfies io.Writer, rotating the file first if this write would
+// push it past policy.MaxSizeBytes.
+func (rw *rotatingFileWriter) Write(p []byte) (int, error) {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	if rw.policy.MaxSizeBytes > 0 && rw.size+int64(len(p)) > rw.policy.MaxSizeBytes {
+		if err := rw.rotateLocked(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := rw.file.Write(p)
+	rw.size += int64(n)
+	if err != nil {
+		return n, fmt.Errorf("write rotating log file: %w", err)
+	}
+	return n, nil
+}
+
+// rotateLocked closes the current file, renames it to a timestamped backup,
+// reopens a fresh file, and prunes old backups. rw.mu must be held.
+func (rw *rotatingFileWriter) rotateLocked() error {
+	if err := rw.file.Close(); err != nil {
+		return fmt.Errorf("close log file for rotation: %w", err)
+	}
+
+	ext := filepath.Ext(rw.filename)
+	stem := strings.TrimSuffix(rw.filename, ext)
+	backupName := fmt.Sprintf("%s-%s%s", stem, time.Now().Format("20060102-150405"), ext)
+
+	oldPath := filepath.Join(rw.baseDir, rw.filename)
+	backupPath := filepath.Join(rw.baseDir, backupName)
+	if err := os.Rename(oldPath, backupPath); err != nil {
+		// The file at oldPath is still the live log; reopen it (append mode,
+		// since it wasn't actually rotated) so Write keeps working.
+		file, reopenErr := openLogFile(rw.filename, rw.allowRead, false)
+		if reopenErr != nil {
+			return fmt.Errorf("rename log file for rotation: %w (also failed to reopen it: %v)", err, reopenErr)
+		}
+		rw.file = file
+		return fmt.Errorf("rename log file for rotation: %w", err)
+	}
+
+	if rw.policy.Compress {
+		if err := compressBackup(backupPath); err != nil {
+			fmt.Fprintf(os.Stderr, "multislog: failed to compress backup %q: %v\n", backupPath, err)
+		}
+	}
+
+	file, err := openLogFile(rw.filename, rw.allowRead, true)
+	if err != nil {
+		return fmt.Errorf("reopen log file after rotation: %w", err)
+	}
+	rw.file = file
+	rw.size = 0
+
+	if err := rw.pruneBackupsLocked(stem, ext); err != nil {
+		fmt.Fprintf(os.Stderr, "multislog: failed to prune backups: %v\n", err)
+	}
+
+	return nil
+}
+
+// compressBackup gzips backupPath in place, replacing it with backupPath+".gz".
+func compressBackup(backupPath string) error {
+	// #nosec G304 -- path is derived from an already-sandboxed log file name
+	src, err := os.Open(backupPath)
+	if err != nil {
+		return fmt.Errorf("open backup for compression: %w", err)
+	}
+	defer src.Close()
+
+	// #nosec G304 -- path is derived from an already-sandboxed log file name
+	dst, err := os.Create(backupPath + ".gz")
+	if err != nil {
+		return fmt.Errorf("create compressed backup: %w", err)
+	}
+	defer dst.Close()
+
+	gw := gzip.NewWriter(dst)
+	if _, err := io.Copy(gw, src); err != nil {
+		return fmt.Errorf("compress backup: %w", err)
+	}
+	if err := gw.Close(); err != nil {
+		return fmt.Errorf("finalize compressed backup: %w", err)
+	}
+
+	if err := os.Remove(backupPath); err != nil {
+		return fmt.Errorf("remove uncompressed backup: %w", err)
+	}
+	return nil
+}
+
+// pruneBackupsLocked deletes rotated backups of stem+ext beyond MaxBackups or
+// older than MaxAgeDays. rw.mu must be held.
+func (rw *rotatingFileWriter) pruneBackupsLocked(stem, ext string) error {
+	matches, err := filepath.Glob(filepath.Join(rw.baseDir, stem+"-*"+ext+"*"))
+	if err != nil {
+		return fmt.Errorf("glob backups: %w", err)
+	}
+
+	type backup struct {
+		path    string
+		modTime time.Time
+	}
+	backups := make([]backup, 0, len(matches))
+	for _, path := range matches {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{path: path, modTime: info.ModTime()})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].modTime.After(backups[j].modTime)
+	})
+
+	now := time.Now()
+	for i, b := range backups {
+		tooOld := rw.policy.MaxAgeDays > 0 && now.Sub(b.modTime) > time.Duration(rw.policy.MaxAgeDays)*24*time.Hour
+		tooMany := rw.policy.MaxBackups > 0 && i >= rw.policy.MaxBackups
+		if tooOld || tooMany {
+			if err := os.Remove(b.path); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("remove backup %q: %w", b.path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Close stops the SIGHUP watcher and closes the underlying file.
+func (rw *rotatingFileWriter) Close() error {
+	signal.Stop(rw.sighup)
+	close(rw.done)
+
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	if err := rw.file.Close(); err != nil {
+		return fmt.Errorf("close rotating log file: %w", err)
+	}
+	return nil
+}
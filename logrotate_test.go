@@ -0,0 +1,189 @@
+package multislog
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func rotatingTestDir(t *testing.T) string {
+	t.Helper()
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatalf("os.Executable: %v", err)
+	}
+	dir, err := filepath.EvalSymlinks(filepath.Dir(exe))
+	if err != nil {
+		t.Fatalf("EvalSymlinks: %v", err)
+	}
+	return dir
+}
+
+// cleanupRotated removes filename and anything matching its rotated-backup
+// naming scheme (stem-timestamp.ext[.gz]) from dir.
+func cleanupRotated(t *testing.T, dir, filename string) {
+	t.Helper()
+	ext := filepath.Ext(filename)
+	stem := filename[:len(filename)-len(ext)]
+	matches, err := filepath.Glob(filepath.Join(dir, stem+"*"))
+	if err != nil {
+		t.Fatalf("glob: %v", err)
+	}
+	for _, m := range matches {
+		_ = os.Remove(m)
+	}
+}
+
+func TestRotatingFileWriter_RotatesAtMaxSize(t *testing.T) {
+	dir := rotatingTestDir(t)
+	filename := "rotate-size.log"
+	defer cleanupRotated(t, dir, filename)
+
+	rw, err := newRotatingFileWriter(filename, false, true, RotationPolicy{MaxSizeBytes: 16})
+	if err != nil {
+		t.Fatalf("newRotatingFileWriter: %v", err)
+	}
+	defer rw.Close()
+
+	if _, err := rw.Write([]byte("0123456789")); err != nil {
+		t.Fatalf("first write: %v", err)
+	}
+	if _, err := rw.Write([]byte("0123456789")); err != nil {
+		t.Fatalf("second write: %v", err)
+	}
+
+	matches, err := filepath.Glob(filepath.Join(dir, "rotate-size-*.log"))
+	if err != nil {
+		t.Fatalf("glob: %v", err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("expected one rotated backup, got %d: %v", len(matches), matches)
+	}
+
+	content := readFile(t, filepath.Join(dir, filename))
+	if content != "0123456789" {
+		t.Fatalf("expected only the post-rotation write in the live file, got %q", content)
+	}
+}
+
+func TestRotatingFileWriter_PruneMaxBackups(t *testing.T) {
+	dir := rotatingTestDir(t)
+	filename := "rotate-prune.log"
+	defer cleanupRotated(t, dir, filename)
+
+	rw, err := newRotatingFileWriter(filename, false, true, RotationPolicy{MaxBackups: 2})
+	if err != nil {
+		t.Fatalf("newRotatingFileWriter: %v", err)
+	}
+	defer rw.Close()
+
+	rw.mu.Lock()
+	for i := 0; i < 3; i++ {
+		if err := rw.rotateLocked(); err != nil {
+			t.Fatalf("rotateLocked: %v", err)
+		}
+		time.Sleep(1100 * time.Millisecond) // backup names are second-resolution
+	}
+	rw.mu.Unlock()
+
+	matches, err := filepath.Glob(filepath.Join(dir, "rotate-prune-*.log"))
+	if err != nil {
+		t.Fatalf("glob: %v", err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("expected MaxBackups to prune to 2 backups, got %d: %v", len(matches), matches)
+	}
+}
+
+func TestRotatingFileWriter_Compress(t *testing.T) {
+	dir := rotatingTestDir(t)
+	filename := "rotate-compress.log"
+	defer cleanupRotated(t, dir, filename)
+
+	rw, err := newRotatingFileWriter(filename, false, true, RotationPolicy{Compress: true})
+	if err != nil {
+		t.Fatalf("newRotatingFileWriter: %v", err)
+	}
+	defer rw.Close()
+
+	if _, err := rw.Write([]byte("hello")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	rw.mu.Lock()
+	err = rw.rotateLocked()
+	rw.mu.Unlock()
+	if err != nil {
+		t.Fatalf("rotateLocked: %v", err)
+	}
+
+	matches, err := filepath.Glob(filepath.Join(dir, "rotate-compress-*.log.gz"))
+	if err != nil {
+		t.Fatalf("glob: %v", err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("expected one compressed backup, got %d: %v", len(matches), matches)
+	}
+
+	// #nosec G304 -- path comes from filepath.Glob over a test-owned directory
+	f, err := os.Open(matches[0])
+	if err != nil {
+		t.Fatalf("open compressed backup: %v", err)
+	}
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("gzip.NewReader: %v", err)
+	}
+	defer gr.Close()
+
+	got, err := io.ReadAll(gr)
+	if err != nil {
+		t.Fatalf("read gzip contents: %v", err)
+	}
+	if string(got) != "hello" {
+		t.Fatalf("compressed backup content = %q, want %q", got, "hello")
+	}
+}
+
+// TestRotatingFileWriter_RecoversFromFailedRename regression-tests the fix
+// where a failed os.Rename during rotation left rw.file closed forever,
+// breaking every subsequent Write. It pre-creates the exact backup path as a
+// directory so os.Rename(file, dir) fails even when running as root.
+func TestRotatingFileWriter_RecoversFromFailedRename(t *testing.T) {
+	dir := rotatingTestDir(t)
+	filename := "rotate-renamefail.log"
+	defer cleanupRotated(t, dir, filename)
+
+	rw, err := newRotatingFileWriter(filename, false, true, RotationPolicy{})
+	if err != nil {
+		t.Fatalf("newRotatingFileWriter: %v", err)
+	}
+	defer rw.Close()
+
+	backupPath := filepath.Join(dir, "rotate-renamefail-"+time.Now().Format("20060102-150405")+".log")
+	if err := os.Mkdir(backupPath, 0o755); err != nil {
+		t.Fatalf("mkdir backup path: %v", err)
+	}
+	defer os.Remove(backupPath)
+
+	rw.mu.Lock()
+	err = rw.rotateLocked()
+	rw.mu.Unlock()
+	if err == nil {
+		t.Fatal("expected rotateLocked to report the rename failure")
+	}
+
+	if _, err := rw.Write([]byte("still alive")); err != nil {
+		t.Fatalf("Write after failed rotation should still succeed, got: %v", err)
+	}
+
+	content := readFile(t, filepath.Join(dir, filename))
+	if content != "still alive" {
+		t.Fatalf("expected file to remain writable after failed rotation, got %q", content)
+	}
+}
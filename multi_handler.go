@@ -3,22 +3,81 @@ package multislog
 import (
 	"context"
 	"log/slog"
+	"sync"
 	"time"
 )
 
+// handlerSet is the mutable, mutex-guarded list of leaf handlers shared
+// between a Multislog and the multihandler backing its slog.Logger. It lets
+// RemoveHandler/ReplaceHandler swap handlers out at runtime without racing
+// against in-flight log calls.
+type handlerSet struct {
+	mu       sync.Mutex
+	handlers []slog.Handler
+}
+
+// newHandlerSet creates a handlerSet seeded with the given handlers.
+func newHandlerSet(handlers []slog.Handler) *handlerSet {
+	return &handlerSet{handlers: handlers}
+}
+
+// append adds h to the set.
+func (s *handlerSet) append(h slog.Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers = append(s.handlers, h)
+}
+
+// snapshot returns a copy of the current handlers, safe to range over
+// without holding the set's lock.
+func (s *handlerSet) snapshot() []slog.Handler {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]slog.Handler, len(s.handlers))
+	copy(out, s.handlers)
+	return out
+}
+
+// remove deletes h from the set by identity. It reports whether h was found.
+func (s *handlerSet) remove(h slog.Handler) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, existing := range s.handlers {
+		if existing == h {
+			s.handlers = append(s.handlers[:i], s.handlers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// replace swaps oldHandler for newHandler in place, preserving fan-out
+// order. It reports whether oldHandler was found.
+func (s *handlerSet) replace(oldHandler, newHandler slog.Handler) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, existing := range s.handlers {
+		if existing == oldHandler {
+			s.handlers[i] = newHandler
+			return true
+		}
+	}
+	return false
+}
+
 // Multihandler is a slice of slog.Handlers. It shadows some slog.Handler
 // methods to ensure relevant log messages are sent to different handlers,
 // since each handler may have different log levels.
 //
 // Multislog uses a single Multihandler object create a new custom logger.
 type multihandler struct {
-	tz       *time.Location
-	handlers []slog.Handler
+	tz  *time.Location
+	set *handlerSet
 }
 
 // Enabled determines if a slog message will be processed.
 func (mh *multihandler) Enabled(ctx context.Context, level slog.Level) bool {
-	for _, h := range mh.handlers {
+	for _, h := range mh.set.snapshot() {
 		if h.Enabled(ctx, level) {
 			return true
 		}
@@ -32,7 +91,7 @@ func (mh *multihandler) Handle(ctx context.Context, r slog.Record) error {
 	if mh.tz != nil {
 		r.Time = r.Time.In(mh.tz)
 	}
-	for _, h := range mh.handlers {
+	for _, h := range mh.set.snapshot() {
 		if h.Enabled(ctx, r.Level) {
 			_ = h.Handle(ctx, r)
 		}
@@ -42,24 +101,26 @@ func (mh *multihandler) Handle(ctx context.Context, r slog.Record) error {
 
 // WithAttrs satisfies handler interface.
 func (mh *multihandler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	hs := make([]slog.Handler, len(mh.handlers))
-	for i, h := range mh.handlers {
+	current := mh.set.snapshot()
+	hs := make([]slog.Handler, len(current))
+	for i, h := range current {
 		hs[i] = h.WithAttrs(attrs)
 	}
 	return &multihandler{
-		handlers: hs,
-		tz:       mh.tz,
+		set: newHandlerSet(hs),
+		tz:  mh.tz,
 	}
 }
 
 // WithGroup satisfies handler interface.
 func (mh *multihandler) WithGroup(name string) slog.Handler {
-	hs := make([]slog.Handler, len(mh.handlers))
-	for i, h := range mh.handlers {
+	current := mh.set.snapshot()
+	hs := make([]slog.Handler, len(current))
+	for i, h := range current {
 		hs[i] = h.WithGroup(name)
 	}
 	return &multihandler{
-		handlers: hs,
-		tz:       mh.tz,
+		set: newHandlerSet(hs),
+		tz:  mh.tz,
 	}
 }
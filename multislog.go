@@ -1,6 +1,6 @@
 // Package multislog is a custom multilogger that plays nice with Go standard library log/slog.
 //
-// It can log to console, a log file and email at the same time, each with a different log level.
+// It can log to console, a log file, email, syslog and journald at the same time, each with a different log level.
 //
 // It is slog-compliant: Anywhere slog is used you can use multislog without having to change any existing code.
 //
@@ -14,6 +14,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -29,9 +30,12 @@ var (
 type Multislog struct {
 	*slog.Logger
 
-	logFile  *os.File
-	timezone *time.Location
-	handlers []slog.Handler
+	rotatingFile *rotatingFileWriter
+	timezone     *time.Location
+	handlers     *handlerSet
+
+	closeOnce sync.Once
+	closeErr  error
 }
 
 // Option type to construct a Multislog object with a variable number of options.
@@ -42,6 +46,13 @@ type Option func(*Multislog) error
 // It is intended to be called as a deferred function at main(), immediately after the logger is instantiated.
 // The deferred Close() function ensures the log file is properly closed on normal shutdown and panic unwinding.
 // The deferred Close() function won't run on: SIGKILL; power loss; kernel panic; or os.Exit.
+// Run installs a signal handler that calls Close() on SIGINT/SIGTERM, which otherwise also end the process without running deferred functions.
+//
+// Any handler Close() failures are joined into the returned error rather than only printed to stderr.
+//
+// Close is idempotent: only the first call actually closes anything, so it's
+// safe to both defer Close() in main() and call it (directly or via Run)
+// during shutdown.
 //
 // Example (main.go):
 //
@@ -49,26 +60,32 @@ type Option func(*Multislog) error
 //	defer msl.Close()
 //
 // See Multislog.New() for complete usage example.
-func (ms *Multislog) Close() {
-	// Close handlers first
-	for _, h := range ms.handlers {
-		c, ok := h.(interface{ Close() error })
-		if ok {
-			err := c.Close()
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "multislog: failed to close handler: %v\n", err)
+func (ms *Multislog) Close() error {
+	ms.closeOnce.Do(func() {
+		var errs []error
+
+		// Close every handler that owns a resource (log file, SMTP client,
+		// syslog/journald connection, etc.).
+		for _, h := range ms.handlers.snapshot() {
+			c, ok := h.(interface{ Close() error })
+			if ok {
+				if err := c.Close(); err != nil {
+					errs = append(errs, fmt.Errorf("close handler: %w", err))
+				}
 			}
 		}
-	}
 
-	// Close log file last
-	if ms.logFile != nil {
-		err := ms.logFile.Close()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "multislog: failed to close log file: %v\n", err)
+		if ms.rotatingFile != nil {
+			if err := ms.rotatingFile.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("close rotating log file: %w", err))
+			}
+			ms.rotatingFile = nil
 		}
-		ms.logFile = nil
-	}
+
+		ms.closeErr = errors.Join(errs...)
+	})
+
+	return ms.closeErr
 }
 
 // New is the primary Multislog constructor. It is typically called in main().
@@ -81,7 +98,7 @@ func (ms *Multislog) Close() {
 //	    EnableTimezone("America/New_York"),
 //	    EnableConsole(slog.LevelDebug),
 //	    EnableLogFile(slog.LevelInfo, "logfile.json", false, true),
-//	    EnableEmail(slog.LevelWarn, "smtp.gmail.com", "465", "admin", "myPassword", "from@gmail.com", "to@email.com"),
+//	    EnableEmail(slog.LevelWarn, "smtp.gmail.com", "465", "admin", "myPassword", "from@gmail.com", "to@email.com", EmailOptions{}),
 //	)
 //	defer msl.Close()
 //	slog.SetDefault(msl.Logger)
@@ -89,7 +106,7 @@ func (ms *Multislog) Close() {
 //
 // By design, New() panics if any options fail to enable at start.
 func New(opts ...Option) *Multislog {
-	ms := &Multislog{}
+	ms := &Multislog{handlers: newHandlerSet(nil)}
 
 	utc := time.UTC
 	ms.timezone = utc
@@ -102,8 +119,8 @@ func New(opts ...Option) *Multislog {
 	}
 
 	mh := &multihandler{
-		handlers: ms.handlers,
-		tz:       ms.timezone,
+		set: ms.handlers,
+		tz:  ms.timezone,
 	}
 	ms.Logger = slog.New(mh)
 	return ms
@@ -130,7 +147,7 @@ func EnableTimezone(timezone string) Option {
 func EnableConsole(level slog.Level) Option {
 	return func(ms *Multislog) error {
 		consoleHandler := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level})
-		ms.handlers = append(ms.handlers, consoleHandler)
+		ms.handlers.append(consoleHandler)
 		return nil
 	}
 }
@@ -141,27 +158,134 @@ func EnableConsole(level slog.Level) Option {
 // clearOnRestart deletes the existing log file on every run (useful when rapid prototyping).
 func EnableLogFile(level slog.Level, filename string, allowRead, clearOnRestart bool) Option {
 	return func(ms *Multislog) error {
-		file, err := openLogFile(filename, allowRead, clearOnRestart)
+		h, err := NewLogFileHandler(level, filename, allowRead, clearOnRestart)
 		if err != nil {
 			return err
 		}
-		ms.logFile = file
-		fileHandler := slog.NewJSONHandler(file, &slog.HandlerOptions{Level: level})
-		ms.handlers = append(ms.handlers, fileHandler)
+		ms.handlers.append(h)
 		return nil
 	}
 }
 
-// EnableEmail outputs all logs above "level" to email.
-func EnableEmail(level slog.Level, host, port, username, password, sender, recipient string) Option {
+// logFileHandler pairs a file-backed slog.Handler with the file itself, so
+// Close() stops writing and releases the file descriptor together.
+type logFileHandler struct {
+	slog.Handler
+	file *os.File
+}
+
+func (h *logFileHandler) Close() error {
+	return h.file.Close()
+}
+
+// NewLogFileHandler constructs the same JSON-formatted, file-backed handler
+// EnableLogFile uses, but hands back the slog.Handler directly. Use it with
+// EnableHandler instead of EnableLogFile when you need a reference to later
+// pass to RemoveHandler or ReplaceHandler, e.g. to rotate to a new log file
+// without restarting the process.
+//
+// allowRead makes the log file world-readable.
+// clearOnRestart deletes the existing log file on every run (useful when rapid prototyping).
+func NewLogFileHandler(level slog.Level, filename string, allowRead, clearOnRestart bool) (slog.Handler, error) {
+	file, err := openLogFile(filename, allowRead, clearOnRestart)
+	if err != nil {
+		return nil, err
+	}
+	return &logFileHandler{
+		Handler: slog.NewJSONHandler(file, &slog.HandlerOptions{Level: level}),
+		file:    file,
+	}, nil
+}
+
+// EnableRotatingLogFile outputs all logs above "level" to a log file that is
+// rotated and pruned according to policy, so the file doesn't grow unbounded.
+//
+// allowRead makes the log file world-readable.
+// clearOnRestart deletes the existing log file on every run (useful when rapid prototyping).
+//
+// The file also rotates on SIGHUP, so external logrotate tools can trigger a
+// rotation without restarting the process.
+func EnableRotatingLogFile(level slog.Level, filename string, allowRead, clearOnRestart bool, policy RotationPolicy) Option {
 	return func(ms *Multislog) error {
-		sc := newSMTPClient(port, host, username, password, sender, recipient)
-		emailHandler := newEmailHandler(sc, level)
-		ms.handlers = append(ms.handlers, emailHandler)
+		rw, err := newRotatingFileWriter(filename, allowRead, clearOnRestart, policy)
+		if err != nil {
+			return err
+		}
+		ms.rotatingFile = rw
+		fileHandler := slog.NewJSONHandler(rw, &slog.HandlerOptions{Level: level})
+		ms.handlers.append(fileHandler)
 		return nil
 	}
 }
 
+// EnableEmail outputs all logs above "level" to email, batched into digests
+// according to opts so a busy logger can't flood the recipient's inbox.
+func EnableEmail(level slog.Level, host, port, username, password, sender, recipient string, opts EmailOptions) Option {
+	return func(ms *Multislog) error {
+		ms.handlers.append(NewEmailHandler(level, host, port, username, password, sender, recipient, opts))
+		return nil
+	}
+}
+
+// NewEmailHandler constructs the same batching email handler EnableEmail
+// uses, but hands back the slog.Handler directly. Use it with EnableHandler
+// instead of EnableEmail when you need a reference to later pass to
+// RemoveHandler or ReplaceHandler, e.g. to rotate the email destination
+// without restarting the process.
+func NewEmailHandler(level slog.Level, host, port, username, password, sender, recipient string, opts EmailOptions) slog.Handler {
+	sc := newSMTPClient(host, port, username, password, sender, recipient)
+	sc.CC = opts.CC
+	sc.BCC = opts.BCC
+	sc.UseTLS = opts.UseTLS
+	sc.UseSTARTTLS = opts.UseSTARTTLS
+
+	return newEmailHandler(sc, level, opts)
+}
+
+// EnableSyslog outputs all logs above "level" to a local or remote syslog daemon.
+//
+// network and addr follow net.Dial conventions (e.g. "udp", "syslogserver:514").
+// An empty network dials the local syslog socket. tag identifies the
+// application in each forwarded record.
+func EnableSyslog(level slog.Level, network, addr, tag string) Option {
+	return func(ms *Multislog) error {
+		ms.handlers.append(NewSyslogHandler(level, network, addr, tag))
+		return nil
+	}
+}
+
+// NewSyslogHandler constructs the same syslog-forwarding handler
+// EnableSyslog uses, but hands back the slog.Handler directly. Use it with
+// EnableHandler instead of EnableSyslog when you need a reference to later
+// pass to RemoveHandler or ReplaceHandler, e.g. to repoint syslog forwarding
+// at a new daemon without restarting the process.
+func NewSyslogHandler(level slog.Level, network, addr, tag string) slog.Handler {
+	return newSyslogHandler(level, network, addr, tag)
+}
+
+// EnableJournald outputs all logs above "level" to systemd-journald.
+//
+// Records are sent over journald's native datagram protocol so slog attrs
+// become structured, queryable journal fields.
+func EnableJournald(level slog.Level) Option {
+	return func(ms *Multislog) error {
+		jh, err := NewJournaldHandler(level)
+		if err != nil {
+			return err
+		}
+		ms.handlers.append(jh)
+		return nil
+	}
+}
+
+// NewJournaldHandler constructs the same journald-forwarding handler
+// EnableJournald uses, but hands back the slog.Handler directly. Use it with
+// EnableHandler instead of EnableJournald when you need a reference to later
+// pass to RemoveHandler or ReplaceHandler.
+func NewJournaldHandler(level slog.Level) (slog.Handler, error) {
+	return newJournaldHandler(level)
+}
+
 // Helper function for multisloggers to set the log file.
 func openLogFile(filename string, allowRead, clearOnRestart bool) (*os.File, error) {
 	// Security checks for validity filename
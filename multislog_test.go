@@ -198,7 +198,8 @@ func TestEnableLogFile_InvalidFilename(t *testing.T) {
 //
 
 type closingHandler struct {
-	closed bool
+	closed    bool
+	failClose bool
 }
 
 func (h *closingHandler) Enabled(context.Context, slog.Level) bool  { return true }
@@ -207,6 +208,9 @@ func (h *closingHandler) WithAttrs([]slog.Attr) slog.Handler        { return h }
 func (h *closingHandler) WithGroup(string) slog.Handler             { return h }
 func (h *closingHandler) Close() error {
 	h.closed = true
+	if h.failClose {
+		return errors.New("close failed")
+	}
 	return nil
 }
 
@@ -214,7 +218,7 @@ func TestClose_ClosesHandlers(t *testing.T) {
 	h := &closingHandler{}
 	ms := &Multislog{
 		Logger:   slog.New(h),
-		handlers: []slog.Handler{h},
+		handlers: newHandlerSet([]slog.Handler{h}),
 	}
 
 	ms.Close()
@@ -0,0 +1,301 @@
+package multislog
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimitPolicy allows the first AllowFirst records per (level, message)
+// key within Interval, then 1-in-ThenEvery thereafter. Distinct keys are
+// tracked in an LRU capped at MaxKeys so memory stays bounded under a wide
+// spread of message templates. An AllowFirst of 0 or less lets every record
+// through, whatever ThenEvery is.
+type RateLimitPolicy struct {
+	Interval   time.Duration
+	AllowFirst int
+	ThenEvery  int
+	MaxKeys    int
+}
+
+// DedupPolicy suppresses identical records (same level, message and attrs)
+// seen again within Window, emitting a single "repeated N times" summary
+// record once the window closes.
+type DedupPolicy struct {
+	Window time.Duration
+}
+
+// SamplingPolicy configures the sampling/deduplication middleware installed
+// by EnableSampling. A nil field disables that half of the policy.
+type SamplingPolicy struct {
+	RateLimit *RateLimitPolicy
+	Dedup     *DedupPolicy
+}
+
+// EnableSampling wraps opt so the handler(s) it registers are sampled
+// according to policy before records reach them. Sampling is applied per
+// handler rather than logger-wide, so e.g. console can log everything while
+// email is heavily sampled:
+//
+//	msl := multislog.New(
+//	    EnableConsole(slog.LevelDebug),
+//	    EnableSampling(emailPolicy, EnableEmail(slog.LevelWarn, ...)),
+//	)
+func EnableSampling(policy SamplingPolicy, opt Option) Option {
+	return func(ms *Multislog) error {
+		before := ms.handlers.snapshot()
+		if err := opt(ms); err != nil {
+			return err
+		}
+		for _, h := range ms.handlers.snapshot()[len(before):] {
+			ms.handlers.replace(h, newSampledHandler(h, policy))
+		}
+		return nil
+	}
+}
+
+// sampledHandler wraps a leaf slog.Handler, rate-limiting and/or
+// deduplicating records before they reach it. It satisfies slog.Handler.
+type sampledHandler struct {
+	next slog.Handler
+
+	rate  *rateSampler
+	dedup *dedupSampler
+}
+
+// newSampledHandler wraps next according to policy.
+func newSampledHandler(next slog.Handler, policy SamplingPolicy) *sampledHandler {
+	sh := &sampledHandler{next: next}
+	if policy.RateLimit != nil {
+		sh.rate = newRateSampler(*policy.RateLimit)
+	}
+	if policy.Dedup != nil {
+		sh.dedup = newDedupSampler(*policy.Dedup, next)
+	}
+	return sh
+}
+
+// Enabled defers to the wrapped handler.
+func (sh *sampledHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return sh.next.Enabled(ctx, level)
+}
+
+// Handle applies rate limiting and then deduplication before forwarding to
+// the wrapped handler.
+func (sh *sampledHandler) Handle(ctx context.Context, r slog.Record) error {
+	if sh.rate != nil && !sh.rate.allow(r) {
+		return nil
+	}
+	if sh.dedup != nil {
+		return sh.dedup.handle(ctx, r)
+	}
+	return sh.next.Handle(ctx, r)
+}
+
+// WithAttrs satisfies handler interface.
+func (sh *sampledHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &sampledHandler{next: sh.next.WithAttrs(attrs), rate: sh.rate, dedup: sh.dedup}
+}
+
+// WithGroup satisfies handler interface.
+func (sh *sampledHandler) WithGroup(name string) slog.Handler {
+	return &sampledHandler{next: sh.next.WithGroup(name), rate: sh.rate, dedup: sh.dedup}
+}
+
+// Close stops any pending dedup timers and closes the wrapped handler if it
+// is itself closeable.
+func (sh *sampledHandler) Close() error {
+	if sh.dedup != nil {
+		sh.dedup.close()
+	}
+	if c, ok := sh.next.(interface{ Close() error }); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// fnvKey hashes parts into a single key, used to bound the rate sampler's LRU
+// and to identify duplicate records for the dedup sampler.
+func fnvKey(parts ...string) uint64 {
+	h := fnv.New64a()
+	for _, p := range parts {
+		_, _ = h.Write([]byte(p))
+		_, _ = h.Write([]byte{0})
+	}
+	return h.Sum64()
+}
+
+// rateSampler allows the first AllowFirst records per (level, message) key
+// within Interval, then 1-in-ThenEvery thereafter, tracking keys in an LRU
+// capped at MaxKeys.
+type rateSampler struct {
+	mu         sync.Mutex
+	interval   time.Duration
+	allowFirst int
+	thenEvery  int
+	maxKeys    int
+
+	order *list.List
+	index map[uint64]*list.Element
+}
+
+// rateCounter is the per-key state tracked by rateSampler's LRU.
+type rateCounter struct {
+	key         uint64
+	windowStart time.Time
+	count       int
+}
+
+func newRateSampler(policy RateLimitPolicy) *rateSampler {
+	return &rateSampler{
+		interval:   policy.Interval,
+		allowFirst: policy.AllowFirst,
+		thenEvery:  policy.ThenEvery,
+		maxKeys:    policy.MaxKeys,
+		order:      list.New(),
+		index:      make(map[uint64]*list.Element),
+	}
+}
+
+// allow reports whether r should pass through, advancing r's (level,
+// message) window and count as a side effect.
+func (rs *rateSampler) allow(r slog.Record) bool {
+	key := fnvKey(r.Level.String(), r.Message)
+	now := time.Now()
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	elem, exists := rs.index[key]
+	var counter *rateCounter
+	if exists {
+		counter = elem.Value.(*rateCounter)
+		rs.order.MoveToFront(elem)
+	} else {
+		counter = &rateCounter{key: key, windowStart: now}
+		rs.index[key] = rs.order.PushFront(counter)
+		rs.evictIfNeededLocked()
+	}
+
+	if rs.interval > 0 && now.Sub(counter.windowStart) > rs.interval {
+		counter.windowStart = now
+		counter.count = 0
+	}
+	counter.count++
+
+	if rs.allowFirst <= 0 || counter.count <= rs.allowFirst {
+		return true
+	}
+	if rs.thenEvery <= 0 {
+		return false
+	}
+	return (counter.count-rs.allowFirst)%rs.thenEvery == 0
+}
+
+// evictIfNeededLocked drops the least-recently-used keys once the LRU grows
+// past maxKeys. rs.mu must be held.
+func (rs *rateSampler) evictIfNeededLocked() {
+	if rs.maxKeys <= 0 {
+		return
+	}
+	for rs.order.Len() > rs.maxKeys {
+		oldest := rs.order.Back()
+		if oldest == nil {
+			return
+		}
+		counter := oldest.Value.(*rateCounter)
+		delete(rs.index, counter.key)
+		rs.order.Remove(oldest)
+	}
+}
+
+// dedupSampler suppresses identical records seen again within window,
+// emitting a single "repeated N times" summary once the window closes.
+type dedupSampler struct {
+	mu      sync.Mutex
+	window  time.Duration
+	next    slog.Handler
+	entries map[uint64]*dedupEntry
+}
+
+// dedupEntry tracks a single (level, message, attrs) key's in-flight window.
+type dedupEntry struct {
+	level slog.Level
+	msg   string
+	count int
+	timer *time.Timer
+}
+
+func newDedupSampler(policy DedupPolicy, next slog.Handler) *dedupSampler {
+	return &dedupSampler{
+		window:  policy.Window,
+		next:    next,
+		entries: make(map[uint64]*dedupEntry),
+	}
+}
+
+// handle forwards the first occurrence of a record's key immediately, and
+// suppresses subsequent identical records until the window closes.
+func (d *dedupSampler) handle(ctx context.Context, r slog.Record) error {
+	key := fnvKey(r.Level.String(), r.Message, recordAttrsKey(r))
+
+	d.mu.Lock()
+	if entry, exists := d.entries[key]; exists {
+		entry.count++
+		d.mu.Unlock()
+		return nil
+	}
+
+	entry := &dedupEntry{level: r.Level, msg: r.Message, count: 1}
+	entry.timer = time.AfterFunc(d.window, func() { d.flush(key) })
+	d.entries[key] = entry
+	d.mu.Unlock()
+
+	return d.next.Handle(ctx, r)
+}
+
+// flush emits a "repeated N times" summary for key if more than the initial
+// record was suppressed, then forgets the key.
+func (d *dedupSampler) flush(key uint64) {
+	d.mu.Lock()
+	entry, exists := d.entries[key]
+	if exists {
+		delete(d.entries, key)
+	}
+	d.mu.Unlock()
+
+	if !exists || entry.count <= 1 {
+		return
+	}
+
+	summary := slog.NewRecord(time.Now(), entry.level, fmt.Sprintf("%s (repeated %d times)", entry.msg, entry.count), 0)
+	_ = d.next.Handle(context.Background(), summary)
+}
+
+// close stops any pending flush timers without emitting their summaries.
+func (d *dedupSampler) close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for key, entry := range d.entries {
+		entry.timer.Stop()
+		delete(d.entries, key)
+	}
+}
+
+// recordAttrsKey renders a record's attrs into a stable string for hashing.
+func recordAttrsKey(r slog.Record) string {
+	var b strings.Builder
+	r.Attrs(func(a slog.Attr) bool {
+		b.WriteString(a.Key)
+		b.WriteByte('=')
+		b.WriteString(a.Value.String())
+		b.WriteByte(' ')
+		return true
+	})
+	return b.String()
+}
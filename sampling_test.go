@@ -0,0 +1,110 @@
+package multislog
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+// countingHandler is exercised both by the calling goroutine and, for dedup
+// summaries, by a time.AfterFunc goroutine, so access to records is guarded.
+type countingHandler struct {
+	mu      sync.Mutex
+	records []slog.Record
+}
+
+func (h *countingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *countingHandler) Handle(_ context.Context, r slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, r)
+	return nil
+}
+
+func (h *countingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *countingHandler) WithGroup(string) slog.Handler      { return h }
+
+// snapshot returns a copy of the records recorded so far, safe to read
+// without racing the background dedup-summary goroutine.
+func (h *countingHandler) snapshot() []slog.Record {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	out := make([]slog.Record, len(h.records))
+	copy(out, h.records)
+	return out
+}
+
+func TestSampledHandler_RateLimit_AllowsFirstNThenSamples(t *testing.T) {
+	inner := &countingHandler{}
+	sh := newSampledHandler(inner, SamplingPolicy{
+		RateLimit: &RateLimitPolicy{
+			Interval:   time.Minute,
+			AllowFirst: 2,
+			ThenEvery:  3,
+			MaxKeys:    10,
+		},
+	})
+
+	for i := 0; i < 8; i++ {
+		r := slog.NewRecord(time.Now(), slog.LevelInfo, "repeated message", 0)
+		if err := sh.Handle(context.Background(), r); err != nil {
+			t.Fatalf("Handle failed: %v", err)
+		}
+	}
+
+	// Allowed: #1, #2 (AllowFirst), then every 3rd after that: #5, #8.
+	if len(inner.snapshot()) != 4 {
+		t.Fatalf("expected 4 records to pass through, got %d", len(inner.snapshot()))
+	}
+}
+
+func TestSampledHandler_Dedup_SuppressesAndSummarizes(t *testing.T) {
+	inner := &countingHandler{}
+	sh := newSampledHandler(inner, SamplingPolicy{
+		Dedup: &DedupPolicy{Window: 20 * time.Millisecond},
+	})
+
+	for i := 0; i < 3; i++ {
+		r := slog.NewRecord(time.Now(), slog.LevelError, "duplicate message", 0)
+		if err := sh.Handle(context.Background(), r); err != nil {
+			t.Fatalf("Handle failed: %v", err)
+		}
+	}
+
+	if len(inner.snapshot()) != 1 {
+		t.Fatalf("expected only the first record to pass through immediately, got %d", len(inner.snapshot()))
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	if len(inner.snapshot()) != 2 {
+		t.Fatalf("expected a summary record after the window closed, got %d", len(inner.snapshot()))
+	}
+	if inner.snapshot()[1].Message != "duplicate message (repeated 3 times)" {
+		t.Fatalf("unexpected summary message: %q", inner.snapshot()[1].Message)
+	}
+}
+
+func TestEnableSampling_WrapsOnlyTargetHandler(t *testing.T) {
+	ms := New(
+		EnableConsole(slog.LevelDebug),
+		EnableSampling(SamplingPolicy{
+			RateLimit: &RateLimitPolicy{Interval: time.Minute, AllowFirst: 0, ThenEvery: 0, MaxKeys: 10},
+		}, EnableHandlerFunc(slog.LevelInfo, func(context.Context, slog.Record) error { return nil })),
+	)
+	defer ms.Close()
+
+	handlers := ms.handlers.snapshot()
+	if len(handlers) != 2 {
+		t.Fatalf("expected 2 handlers, got %d", len(handlers))
+	}
+	if _, ok := handlers[0].(*sampledHandler); ok {
+		t.Fatal("console handler should not be wrapped in sampledHandler")
+	}
+	if _, ok := handlers[1].(*sampledHandler); !ok {
+		t.Fatal("expected the handler-func handler to be wrapped in sampledHandler")
+	}
+}
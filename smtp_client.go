@@ -1,8 +1,11 @@
 package multislog
 
 import (
+	"crypto/tls"
 	"fmt"
+	"net"
 	"net/smtp"
+	"strings"
 )
 
 // smtpClient contains config settings for a simple SMTP client.
@@ -13,6 +16,11 @@ type smtpClient struct {
 	Password  string
 	Sender    string
 	Recipient string
+	CC        []string
+	BCC       []string
+
+	UseTLS      bool
+	UseSTARTTLS bool
 }
 
 // newSMTPClient initializes a simple SMTP client.
@@ -27,14 +35,95 @@ func newSMTPClient(host, port, username, password, sender, recipient string) *sm
 	}
 }
 
-// Send a simple email based on previously set config settings.
-func (sc *smtpClient) Send(subject, body, recipient string) error {
+// Send an email based on previously set config settings, delivering to
+// Recipient, CC and BCC.
+func (sc *smtpClient) Send(subject, body string) error {
 	addr := fmt.Sprintf("%s:%s", sc.Host, sc.Port)
 	auth := smtp.PlainAuth("", sc.Username, sc.Password, sc.Host)
-	msg := fmt.Sprintf("To: %s\r\nSubject: %s\r\n\r\n%s", recipient, subject, body)
-	err := smtp.SendMail(addr, auth, sc.Sender, []string{recipient}, []byte(msg))
+
+	recipients := make([]string, 0, 1+len(sc.CC)+len(sc.BCC))
+	recipients = append(recipients, sc.Recipient)
+	recipients = append(recipients, sc.CC...)
+	recipients = append(recipients, sc.BCC...)
+
+	headers := fmt.Sprintf("To: %s\r\n", sc.Recipient)
+	if len(sc.CC) > 0 {
+		headers += fmt.Sprintf("Cc: %s\r\n", strings.Join(sc.CC, ", "))
+	}
+	// subject can come from a user-supplied SubjectTemplate executed against
+	// logged record content, so it's stripped of CR/LF before being folded
+	// into a header line; otherwise a logged message containing "\r\n" could
+	// inject arbitrary extra headers (e.g. a Bcc:) into the outgoing email.
+	headers += fmt.Sprintf("Subject: %s\r\n\r\n", sanitizeHeaderValue(subject))
+	msg := []byte(headers + body)
+
+	if sc.UseTLS || sc.UseSTARTTLS {
+		return sc.sendTLS(addr, auth, recipients, msg)
+	}
+
+	err := smtp.SendMail(addr, auth, sc.Sender, recipients, msg)
 	if err != nil {
 		return fmt.Errorf("SMTP Client couldn't send mail. Error: %w", err)
 	}
 	return nil
 }
+
+// sendTLS sends msg over an implicit-TLS or STARTTLS-upgraded connection.
+func (sc *smtpClient) sendTLS(addr string, auth smtp.Auth, recipients []string, msg []byte) error {
+	tlsConfig := &tls.Config{ServerName: sc.Host, MinVersion: tls.VersionTLS12}
+
+	var conn net.Conn
+	var err error
+	if sc.UseSTARTTLS {
+		conn, err = net.Dial("tcp", addr)
+	} else {
+		conn, err = tls.Dial("tcp", addr, tlsConfig)
+	}
+	if err != nil {
+		return fmt.Errorf("SMTP Client couldn't connect: %w", err)
+	}
+	defer conn.Close()
+
+	client, err := smtp.NewClient(conn, sc.Host)
+	if err != nil {
+		return fmt.Errorf("SMTP Client couldn't start session: %w", err)
+	}
+	defer client.Close()
+
+	if sc.UseSTARTTLS {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("SMTP Client couldn't upgrade to TLS: %w", err)
+		}
+	}
+
+	if err := client.Auth(auth); err != nil {
+		return fmt.Errorf("SMTP Client couldn't authenticate: %w", err)
+	}
+	if err := client.Mail(sc.Sender); err != nil {
+		return fmt.Errorf("SMTP Client couldn't set sender: %w", err)
+	}
+	for _, r := range recipients {
+		if err := client.Rcpt(r); err != nil {
+			return fmt.Errorf("SMTP Client couldn't set recipient %q: %w", r, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("SMTP Client couldn't open data writer: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("SMTP Client couldn't write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("SMTP Client couldn't finalize message: %w", err)
+	}
+	return client.Quit()
+}
+
+// sanitizeHeaderValue strips CR and LF from a value about to be folded into
+// a single RFC 5322 header line, preventing header injection from values
+// that may carry attacker- or application-controlled content.
+func sanitizeHeaderValue(value string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(value)
+}
@@ -0,0 +1,43 @@
+package multislog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitizeHeaderValue(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{"normal subject", "normal subject"},
+		{"evil\r\nBcc: attacker@evil.com", "evilBcc: attacker@evil.com"},
+		{"evil\nBcc: attacker@evil.com", "evilBcc: attacker@evil.com"},
+	}
+	for _, c := range cases {
+		if got := sanitizeHeaderValue(c.in); got != c.want {
+			t.Errorf("sanitizeHeaderValue(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
+
+func TestSmtpClient_Send_StripsCRLFFromSubject(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	host, port := server.hostPort()
+	sc := newSMTPClient(host, port, "", "", "from@example.com", "to@example.com")
+
+	injected := "Log Alert\r\nBcc: attacker@evil.com"
+	if err := sc.Send(injected, "body"); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	header := server.lastHeader()
+	for _, line := range strings.Split(header, "\n") {
+		if strings.HasPrefix(line, "Bcc:") {
+			t.Fatalf("expected no injected Bcc header, got header block: %q", header)
+		}
+	}
+	if !strings.Contains(header, "Subject: Log AlertBcc: attacker@evil.com") {
+		t.Fatalf("expected the injection attempt to collapse onto the Subject line, got: %q", header)
+	}
+}
@@ -0,0 +1,270 @@
+package multislog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// syslogSeverity mirrors the RFC 5424 severity levels multislog maps slog
+// levels onto.
+type syslogSeverity int
+
+const (
+	severityErr     syslogSeverity = 3
+	severityWarning syslogSeverity = 4
+	severityInfo    syslogSeverity = 6
+	severityDebug   syslogSeverity = 7
+)
+
+const syslogFacilityUser = 1 // RFC 5424 facility "user-level messages"
+
+const (
+	syslogMinBackoff  = 100 * time.Millisecond
+	syslogMaxBackoff  = 30 * time.Second
+	syslogDialTimeout = 5 * time.Second
+	syslogQueueBuffer = 1000
+)
+
+// syslogCore is the background writer state shared by a syslogHandler and
+// every handler WithAttrs derives from it.
+type syslogCore struct {
+	Level   slog.Level
+	network string
+	addr    string
+	tag     string
+
+	lines chan string
+	done  chan struct{}
+	wg    sync.WaitGroup
+
+	// conn and backoff are only touched by the background run() goroutine.
+	conn    net.Conn
+	backoff time.Duration
+}
+
+// syslogHandler forwards minLevel and above slog messages to a local or
+// remote syslog daemon. It satisfies slog.Handler.
+//
+// Records are queued and written by a single background goroutine, so a
+// slow or unreachable syslog daemon never blocks the caller's Handle() past
+// enqueuing onto a bounded channel: if syslog can't keep up, new records are
+// dropped rather than stalling every goroutine that logs.
+type syslogHandler struct {
+	core  *syslogCore
+	attrs []slog.Attr
+}
+
+// newSyslogHandler creates a custom slog.Handler that forwards records to syslog.
+//
+// network and addr follow net.Dial conventions (e.g. "udp", "syslog.example.com:514").
+// An empty network dials the local syslog socket at addr, defaulting addr to
+// "/dev/log" if it is also empty.
+func newSyslogHandler(level slog.Level, network, addr, tag string) *syslogHandler {
+	if network == "" && addr == "" {
+		addr = "/dev/log"
+	}
+	core := &syslogCore{
+		Level:   level,
+		network: network,
+		addr:    addr,
+		tag:     tag,
+		lines:   make(chan string, syslogQueueBuffer),
+		done:    make(chan struct{}),
+	}
+
+	core.wg.Add(1)
+	go core.run()
+
+	return &syslogHandler{core: core}
+}
+
+// run owns core.conn and core.backoff exclusively, writing queued lines and
+// reconnecting (with backoff) off the caller's goroutine.
+func (core *syslogCore) run() {
+	defer core.wg.Done()
+
+	for {
+		select {
+		case line := <-core.lines:
+			core.writeWithReconnect(line)
+
+		case <-core.done:
+			// Drain whatever queued up since the last write, then exit.
+			for {
+				select {
+				case line := <-core.lines:
+					core.writeWithReconnect(line)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// writeWithReconnect writes line to syslog, reconnecting (and sleeping
+// through backoff) on this goroutine only if necessary.
+func (core *syslogCore) writeWithReconnect(line string) {
+	if core.conn == nil {
+		if err := core.connectWithBackoff(); err != nil {
+			fmt.Fprintf(os.Stderr, "multislog: syslog unreachable, dropping record: %v\n", err)
+			return
+		}
+	}
+
+	if _, err := core.conn.Write([]byte(line)); err != nil {
+		_ = core.conn.Close()
+		core.conn = nil
+
+		if err := core.connectWithBackoff(); err != nil {
+			fmt.Fprintf(os.Stderr, "multislog: syslog reconnect failed, dropping record: %v\n", err)
+			return
+		}
+		if _, err := core.conn.Write([]byte(line)); err != nil {
+			fmt.Fprintf(os.Stderr, "multislog: syslog write failed after reconnect, dropping record: %v\n", err)
+		}
+	}
+}
+
+// connect dials the syslog daemon, bounding the attempt with syslogDialTimeout
+// so a black-holed network never hangs the background goroutine indefinitely.
+func (core *syslogCore) connect() error {
+	network := core.network
+	if network == "" {
+		network = "unixgram"
+	}
+	conn, err := net.DialTimeout(network, core.addr, syslogDialTimeout)
+	if err != nil {
+		return fmt.Errorf("dial syslog %s %s: %w", network, core.addr, err)
+	}
+	core.conn = conn
+	return nil
+}
+
+// connectWithBackoff retries connect(), sleeping through an exponential
+// backoff on failure. It only ever runs on the background run() goroutine,
+// so the sleep never blocks a caller logging through Handle().
+func (core *syslogCore) connectWithBackoff() error {
+	err := core.connect()
+	if err == nil {
+		core.backoff = 0
+		return nil
+	}
+
+	if core.backoff == 0 {
+		core.backoff = syslogMinBackoff
+	}
+	time.Sleep(core.backoff)
+	core.backoff *= 2
+	if core.backoff > syslogMaxBackoff {
+		core.backoff = syslogMaxBackoff
+	}
+	return err
+}
+
+// Enabled determines if a slog message will be forwarded to syslog.
+func (sh *syslogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= sh.core.Level
+}
+
+// severityFor maps a slog level onto its syslog severity.
+func severityFor(level slog.Level) syslogSeverity {
+	switch {
+	case level >= slog.LevelError:
+		return severityErr
+	case level >= slog.LevelWarn:
+		return severityWarning
+	case level >= slog.LevelInfo:
+		return severityInfo
+	default:
+		return severityDebug
+	}
+}
+
+// Handle renders the slog message, including any attrs bound via WithAttrs,
+// and queues it for the background writer. If the queue is full (syslog
+// can't keep up), the record is dropped rather than blocking the caller.
+func (sh *syslogHandler) Handle(_ context.Context, r slog.Record) error {
+	pri := syslogFacilityUser*8 + int(severityFor(r.Level))
+	rendered := formatSyslogMessage(r, sh.attrs)
+
+	var line string
+	if sh.core.network == "" {
+		// Local transport: simple "tag: message" framing.
+		line = fmt.Sprintf("<%d>%s: %s\n", pri, sh.core.tag, rendered)
+	} else {
+		// Network transport: RFC 5424.
+		line = fmt.Sprintf("<%d>1 %s %s %s - - - %s\n",
+			pri, r.Time.Format(time.RFC3339), syslogHostname(), sh.core.tag, rendered)
+	}
+
+	select {
+	case sh.core.lines <- line:
+		return nil
+	default:
+		return fmt.Errorf("syslog handler queue full, dropping record")
+	}
+}
+
+// formatSyslogMessage renders the slog message, its WithAttrs-bound attrs,
+// and its per-call attrs as a single line.
+func formatSyslogMessage(r slog.Record, boundAttrs []slog.Attr) string {
+	msg := r.Message
+	for _, a := range boundAttrs {
+		msg += fmt.Sprintf(" %s=%v", a.Key, a.Value)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		msg += fmt.Sprintf(" %s=%v", a.Key, a.Value)
+		return true
+	})
+	return msg
+}
+
+func syslogHostname() string {
+	h, err := os.Hostname()
+	if err != nil {
+		return "-"
+	}
+	return h
+}
+
+// WithAttrs returns a handler that shares this one's background writer but
+// renders attrs on every subsequent record, matching the slog.Logger.With
+// contract.
+func (sh *syslogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return sh
+	}
+	merged := make([]slog.Attr, 0, len(sh.attrs)+len(attrs))
+	merged = append(merged, sh.attrs...)
+	merged = append(merged, attrs...)
+	return &syslogHandler{core: sh.core, attrs: merged}
+}
+
+// WithGroup satisfies handler interface.
+//
+// Groups aren't supported: syslog records are a single flat line, so there's
+// nowhere to nest a group's attrs. Returning sh unchanged means grouped
+// attrs are currently rendered ungrouped rather than dropped.
+func (sh *syslogHandler) WithGroup(_ string) slog.Handler {
+	return sh
+}
+
+// Close stops the background writer (after draining queued records) and
+// closes the underlying syslog connection.
+func (sh *syslogHandler) Close() error {
+	close(sh.core.done)
+	sh.core.wg.Wait()
+
+	if sh.core.conn == nil {
+		return nil
+	}
+	err := sh.core.conn.Close()
+	sh.core.conn = nil
+	return err
+}
@@ -0,0 +1,145 @@
+package multislog
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSyslogHandler_LocalTransport(t *testing.T) {
+	sockPath := filepath.Join(t.TempDir(), "syslog.sock")
+
+	listener, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: sockPath, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer listener.Close()
+
+	sh := newSyslogHandler(slog.LevelInfo, "", sockPath, "myapp")
+	defer sh.Close()
+
+	r := slog.NewRecord(time.Now(), slog.LevelError, "disk full", 0)
+	r.AddAttrs(slog.String("path", "/var"))
+	if err := sh.Handle(context.Background(), r); err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	_ = listener.SetReadDeadline(time.Now().Add(2 * time.Second))
+	buf := make([]byte, 1024)
+	n, err := listener.Read(buf)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+
+	got := string(buf[:n])
+	if !strings.Contains(got, "myapp: disk full path=/var") {
+		t.Fatalf("unexpected syslog line: %q", got)
+	}
+	// Facility "user" (1) * 8 + severity "err" (3) = 11.
+	if !strings.HasPrefix(got, "<11>") {
+		t.Fatalf("unexpected priority prefix: %q", got)
+	}
+}
+
+func TestSyslogHandler_NetworkTransportUsesRFC5424(t *testing.T) {
+	packetConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer packetConn.Close()
+
+	sh := newSyslogHandler(slog.LevelInfo, "udp", packetConn.LocalAddr().String(), "myapp")
+	defer sh.Close()
+
+	r := slog.NewRecord(time.Now(), slog.LevelWarn, "low disk", 0)
+	if err := sh.Handle(context.Background(), r); err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	_ = packetConn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	buf := make([]byte, 1024)
+	n, _, err := packetConn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+
+	got := string(buf[:n])
+	// Facility "user" (1) * 8 + severity "warning" (4) = 12; RFC 5424 starts "<PRI>1 ".
+	if !strings.HasPrefix(got, "<12>1 ") {
+		t.Fatalf("expected RFC 5424 framing, got %q", got)
+	}
+	if !strings.Contains(got, "myapp") || !strings.Contains(got, "low disk") {
+		t.Fatalf("unexpected syslog line: %q", got)
+	}
+}
+
+func TestSyslogHandler_WithAttrsBindsAttrsIntoLine(t *testing.T) {
+	sockPath := filepath.Join(t.TempDir(), "syslog.sock")
+
+	listener, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: sockPath, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer listener.Close()
+
+	sh := newSyslogHandler(slog.LevelInfo, "", sockPath, "myapp")
+	defer sh.Close()
+
+	bound := sh.WithAttrs([]slog.Attr{slog.String("request_id", "abc123")})
+
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "hello", 0)
+	if err := bound.Handle(context.Background(), r); err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	_ = listener.SetReadDeadline(time.Now().Add(2 * time.Second))
+	buf := make([]byte, 1024)
+	n, err := listener.Read(buf)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+
+	got := string(buf[:n])
+	if !strings.Contains(got, "hello request_id=abc123") {
+		t.Fatalf("expected bound attrs in syslog line, got %q", got)
+	}
+}
+
+func TestSyslogHandler_QueueFullDropsRecord(t *testing.T) {
+	sh := &syslogHandler{
+		core: &syslogCore{
+			Level:   slog.LevelInfo,
+			network: "unixgram",
+			addr:    filepath.Join(t.TempDir(), "nonexistent.sock"),
+			tag:     "myapp",
+			lines:   make(chan string), // unbuffered: nothing is draining it
+			done:    make(chan struct{}),
+		},
+	}
+
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "hello", 0)
+	if err := sh.Handle(context.Background(), r); err == nil {
+		t.Fatal("expected an error when the queue has no room and no reader")
+	}
+}
+
+func TestSeverityFor(t *testing.T) {
+	cases := []struct {
+		level slog.Level
+		want  syslogSeverity
+	}{
+		{slog.LevelDebug, severityDebug},
+		{slog.LevelInfo, severityInfo},
+		{slog.LevelWarn, severityWarning},
+		{slog.LevelError, severityErr},
+	}
+	for _, c := range cases {
+		if got := severityFor(c.level); got != c.want {
+			t.Errorf("severityFor(%v) = %v, want %v", c.level, got, c.want)
+		}
+	}
+}